@@ -0,0 +1,175 @@
+// Package bitset implements a set for small, non-negative ints
+// as a slice of bits.
+//
+// It is an express design decision to hard-code
+// this set just for the int type rather than for
+// the empty interface.
+//
+// It has the same Has/Put/Delete/PutSlice methods as ints/set.Set,
+// but where ints/set.Set spends a map entry on every member, a
+// bitset spends one bit on every int from 0 up to its largest
+// member. That makes it much smaller and faster for dense sets
+// of small IDs, and it also allows set algebra to work on 64
+// members at a time.
+package bitset
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const wordSize = 64
+
+// Set holds the bits of the bitset. Bit i of words[i/64]
+// is set when i is a member of the set.
+type Set struct {
+	words []uint64
+}
+
+// New returns a new empty bitset.
+func New() *Set {
+	return &Set{}
+}
+
+// NewWithCapacity returns a new empty bitset with room for
+// the ints 0 through capacity-1 without growing.
+func NewWithCapacity(capacity int) *Set {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &Set{
+		words: make([]uint64, (capacity+wordSize-1)/wordSize),
+	}
+}
+
+func (s *Set) Has(k int) bool {
+	if k < 0 {
+		return false
+	}
+	w := k / wordSize
+	if w >= len(s.words) {
+		return false
+	}
+	return s.words[w]&(1<<(uint(k)%wordSize)) != 0
+}
+
+// Put puts k in the set, growing the set to fit it if need be.
+// Only non-negative ints can be members; Put panics if k is negative.
+func (s *Set) Put(k int) {
+	if k < 0 {
+		panic(fmt.Sprintf("bitset: cannot put negative int %d", k))
+	}
+	w := k / wordSize
+	s.grow(w + 1)
+	s.words[w] |= 1 << (uint(k) % wordSize)
+}
+
+func (s *Set) Delete(k int) {
+	if k < 0 {
+		return
+	}
+	w := k / wordSize
+	if w >= len(s.words) {
+		return
+	}
+	s.words[w] &^= 1 << (uint(k) % wordSize)
+}
+
+func (s *Set) PutSlice(ks []int) {
+	for _, k := range ks {
+		s.Put(k)
+	}
+}
+
+// grow makes sure the set has at least n words, doubling
+// the backing slice if it has to grow.
+func (s *Set) grow(n int) {
+	if n <= len(s.words) {
+		return
+	}
+	if n <= cap(s.words) {
+		s.words = s.words[:n]
+		return
+	}
+	newCap := 2 * cap(s.words)
+	if newCap < n {
+		newCap = n
+	}
+	newWords := make([]uint64, n, newCap)
+	copy(newWords, s.words)
+	s.words = newWords
+}
+
+// Count returns the number of members of the set.
+func (s *Set) Count() int {
+	n := 0
+	for _, w := range s.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// NextSet returns the smallest member of the set that is >= i.
+// It returns false if there is no such member. To iterate
+// through every member in ascending order:
+//
+//	for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) {
+//		...
+//	}
+func (s *Set) NextSet(i int) (int, bool) {
+	if i < 0 {
+		i = 0
+	}
+	w := i / wordSize
+	if w >= len(s.words) {
+		return 0, false
+	}
+	// Look in the rest of the first word, then in whole words.
+	word := s.words[w] >> (uint(i) % wordSize)
+	if word != 0 {
+		return i + bits.TrailingZeros64(word), true
+	}
+	for w++; w < len(s.words); w++ {
+		if s.words[w] != 0 {
+			return w*wordSize + bits.TrailingZeros64(s.words[w]), true
+		}
+	}
+	return 0, false
+}
+
+// Union puts every member of other into s.
+func (s *Set) Union(other *Set) {
+	s.grow(len(other.words))
+	for i, w := range other.words {
+		s.words[i] |= w
+	}
+}
+
+// Intersect deletes every member of s that is not in other.
+func (s *Set) Intersect(other *Set) {
+	for i := range s.words {
+		if i < len(other.words) {
+			s.words[i] &= other.words[i]
+		} else {
+			s.words[i] = 0
+		}
+	}
+}
+
+// Difference deletes every member of other from s.
+func (s *Set) Difference(other *Set) {
+	n := len(s.words)
+	if len(other.words) < n {
+		n = len(other.words)
+	}
+	for i := 0; i < n; i++ {
+		s.words[i] &^= other.words[i]
+	}
+}
+
+// Clone returns a copy of the set.
+func (s *Set) Clone() *Set {
+	words := make([]uint64, len(s.words))
+	copy(words, s.words)
+	return &Set{words: words}
+}
@@ -0,0 +1,93 @@
+package bitset
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test(t *testing.T) {
+	s := New()
+	s.PutSlice([]int{1, 2, 3})
+	for i := 4; i <= 6; i++ {
+		s.Put(i)
+	}
+	for i := 1; i <= 6; i++ {
+		if !s.Has(i) {
+			t.Errorf("Expected %v to be in the set", i)
+		}
+	}
+	for i := 4; i <= 6; i++ {
+		s.Delete(i)
+	}
+	for i := 4; i <= 6; i++ {
+		if s.Has(i) {
+			t.Errorf("Did not expect %v to be in the set", i)
+		}
+	}
+	if s.Has(-1) || s.Has(1000) {
+		t.Error("Did not expect ints outside the set to be in the set")
+	}
+	s.Delete(-1)
+	s.Delete(1000)
+}
+
+func TestPutNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Put to panic on a negative int")
+		}
+	}()
+	New().Put(-1)
+}
+
+func members(s *Set) []int {
+	got := []int{}
+	for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) {
+		got = append(got, i)
+	}
+	return got
+}
+
+func TestNextSetAndCount(t *testing.T) {
+	s := New()
+	want := []int{0, 5, 63, 64, 65, 200, 100000}
+	s.PutSlice(want)
+	if got := members(s); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if s.Count() != len(want) {
+		t.Errorf("Expected count %v, got %v", len(want), s.Count())
+	}
+	if i, ok := s.NextSet(201); !ok || i != 100000 {
+		t.Errorf("Expected NextSet(201) to be 100000, got %v %v", i, ok)
+	}
+	if _, ok := s.NextSet(100001); ok {
+		t.Error("Expected no member after 100000")
+	}
+}
+
+func TestAlgebra(t *testing.T) {
+	a := NewWithCapacity(10)
+	a.PutSlice([]int{1, 2, 3, 100})
+	b := New()
+	b.PutSlice([]int{2, 3, 4, 500})
+
+	u := a.Clone()
+	u.Union(b)
+	if got, want := members(u), []int{1, 2, 3, 4, 100, 500}; !slices.Equal(want, got) {
+		t.Errorf("Union: expected %v, got %v", want, got)
+	}
+	i := a.Clone()
+	i.Intersect(b)
+	if got, want := members(i), []int{2, 3}; !slices.Equal(want, got) {
+		t.Errorf("Intersect: expected %v, got %v", want, got)
+	}
+	d := b.Clone()
+	d.Difference(a)
+	if got, want := members(d), []int{4, 500}; !slices.Equal(want, got) {
+		t.Errorf("Difference: expected %v, got %v", want, got)
+	}
+	if got, want := members(a), []int{1, 2, 3, 100}; !slices.Equal(want, got) {
+		t.Errorf("Clone should not share bits: expected %v, got %v", want, got)
+	}
+}
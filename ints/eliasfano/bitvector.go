@@ -0,0 +1,111 @@
+package eliasfano
+
+import "math/bits"
+
+// wordsPerBlock is how many words of a bitVector share one rank
+// sample. Larger blocks save memory; smaller ones mean less
+// scanning within a block.
+const wordsPerBlock = 8
+
+// bitVector is a fixed sequence of bits that can answer rank
+// and select queries.
+type bitVector struct {
+	words  []uint64
+	length int
+	// blockRank[b] is the number of ones before block b.
+	// It has one extra entry at the end holding the total.
+	blockRank []int
+}
+
+func newBitVector(words []uint64, length int) *bitVector {
+	v := &bitVector{words: words, length: length}
+	numBlocks := (len(words) + wordsPerBlock - 1) / wordsPerBlock
+	v.blockRank = make([]int, numBlocks+1)
+	ones := 0
+	for b := 0; b < numBlocks; b++ {
+		v.blockRank[b] = ones
+		for _, w := range words[b*wordsPerBlock : min((b+1)*wordsPerBlock, len(words))] {
+			ones += bits.OnesCount64(w)
+		}
+	}
+	v.blockRank[numBlocks] = ones
+	return v
+}
+
+func (v *bitVector) get(i int) bool {
+	return v.words[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+// rank1 returns the number of ones before position i.
+func (v *bitVector) rank1(i int) int {
+	w := i / 64
+	b := w / wordsPerBlock
+	n := v.blockRank[b]
+	for j := b * wordsPerBlock; j < w; j++ {
+		n += bits.OnesCount64(v.words[j])
+	}
+	if i%64 != 0 {
+		n += bits.OnesCount64(v.words[w] & (1<<(uint(i)%64) - 1))
+	}
+	return n
+}
+
+// select1 returns the position of the k-th one, counting from 0.
+// k must be less than the number of ones.
+func (v *bitVector) select1(k int) int {
+	// Find the last block with fewer than k+1 ones before it.
+	lo, hi := 0, len(v.blockRank)-1
+	for lo < hi {
+		mid := int(uint(lo+hi+1) >> 1)
+		if v.blockRank[mid] <= k {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	k -= v.blockRank[lo]
+	for w := lo * wordsPerBlock; ; w++ {
+		word := v.words[w]
+		n := bits.OnesCount64(word)
+		if k < n {
+			return w*64 + selectInWord(word, k)
+		}
+		k -= n
+	}
+}
+
+// select0 returns the position of the k-th zero, counting from 0.
+// k must be less than the number of zeros.
+func (v *bitVector) select0(k int) int {
+	zerosBefore := func(b int) int {
+		return b*wordsPerBlock*64 - v.blockRank[b]
+	}
+	lo, hi := 0, len(v.blockRank)-2
+	for lo < hi {
+		mid := int(uint(lo+hi+1) >> 1)
+		if zerosBefore(mid) <= k {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	k -= zerosBefore(lo)
+	for w := lo * wordsPerBlock; ; w++ {
+		word := ^v.words[w]
+		n := bits.OnesCount64(word)
+		if k < n {
+			return w*64 + selectInWord(word, k)
+		}
+		k -= n
+	}
+}
+
+// selectInWord returns the position of the k-th one in word.
+func selectInWord(word uint64, k int) int {
+	// Clear the k lowest set bits; the lowest remaining
+	// set bit is the one we want.
+	for ; k > 0; k-- {
+		word &= word - 1
+	}
+	return bits.TrailingZeros64(word)
+}
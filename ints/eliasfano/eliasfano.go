@@ -0,0 +1,258 @@
+// Package eliasfano implements an immutable, compressed set of
+// non-negative ints using Elias-Fano encoding.
+//
+// It is an express design decision to hard-code
+// this set just for the int type rather than for
+// the empty interface.
+//
+// A set of n ints, the largest of which is less than u, takes
+// about 2 + log2(u/n) bits per member, no matter how the members
+// are spread out. That is far smaller than ints/set or a slice,
+// which is what makes it worth shipping large, static, sorted
+// lists of IDs in this form.
+//
+// Each member is split into its low l bits and its remaining high
+// bits. The low bits of every member are packed one after another.
+// The high bits are stored in unary: member i sets bit
+// (high bits of member i) + i of a bit vector, which supports rank
+// and select queries, so member i can be found with a single
+// select.
+package eliasfano
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+var InvalidFormat = errors.New("Invalid Elias-Fano Format")
+
+type UnsortedInputError struct {
+	msg string
+}
+
+func (e *UnsortedInputError) Error() string {
+	return e.msg
+}
+
+// Set holds the encoded members of the set.
+type Set struct {
+	n int
+	// lowBits is how many low bits of each member are
+	// stored in lows.
+	lowBits uint
+	lows    []uint64
+	highs   *bitVector
+	max     int
+}
+
+// New returns a new set holding the members of sorted, which must
+// be non-negative and in strictly ascending order.
+func New(sorted []int) (*Set, error) {
+	for i, x := range sorted {
+		if x < 0 {
+			return nil, &UnsortedInputError{
+				msg: fmt.Sprintf("member %d at index %d is negative", x, i),
+			}
+		}
+		if i > 0 && x <= sorted[i-1] {
+			return nil, &UnsortedInputError{
+				msg: fmt.Sprintf("member %d at index %d is not greater than %d", x, i, sorted[i-1]),
+			}
+		}
+	}
+	s := &Set{n: len(sorted)}
+	if s.n == 0 {
+		s.highs = newBitVector(nil, 0)
+		return s, nil
+	}
+	s.max = sorted[s.n-1]
+	// Choose the number of low bits so that the high bits
+	// average out at about one bucket per member.
+	if ratio := (s.max + 1) / s.n; ratio > 1 {
+		s.lowBits = uint(bits.Len(uint(ratio)) - 1)
+	}
+	s.lows = make([]uint64, (s.n*int(s.lowBits)+63)/64)
+	highLength := s.n + s.max>>s.lowBits + 1
+	highWords := make([]uint64, (highLength+63)/64)
+	for i, x := range sorted {
+		s.setLow(i, uint64(x))
+		p := x>>s.lowBits + i
+		highWords[p/64] |= 1 << (uint(p) % 64)
+	}
+	s.highs = newBitVector(highWords, highLength)
+	return s, nil
+}
+
+func (s *Set) setLow(i int, x uint64) {
+	if s.lowBits == 0 {
+		return
+	}
+	x &= 1<<s.lowBits - 1
+	p := uint(i) * s.lowBits
+	w, off := p/64, p%64
+	s.lows[w] |= x << off
+	// The low bits may spill over into the next word.
+	if off+s.lowBits > 64 {
+		s.lows[w+1] |= x >> (64 - off)
+	}
+}
+
+func (s *Set) low(i int) int {
+	if s.lowBits == 0 {
+		return 0
+	}
+	p := uint(i) * s.lowBits
+	w, off := p/64, p%64
+	x := s.lows[w] >> off
+	if off+s.lowBits > 64 {
+		x |= s.lows[w+1] << (64 - off)
+	}
+	return int(x & (1<<s.lowBits - 1))
+}
+
+// Len returns the number of members of the set.
+func (s *Set) Len() int {
+	return s.n
+}
+
+// Contains tells you whether x is in the set.
+func (s *Set) Contains(x int) bool {
+	y, ok := s.NextGEQ(x)
+	return ok && y == x
+}
+
+// Select returns the i-th smallest member of the set, counting
+// from 0. It returns false if i is out of range.
+func (s *Set) Select(i int) (int, bool) {
+	if i < 0 || i >= s.n {
+		return 0, false
+	}
+	high := s.highs.select1(i) - i
+	return high<<s.lowBits | s.low(i), true
+}
+
+// Rank returns the number of members of the set that are <= x.
+func (s *Set) Rank(x int) int {
+	if s.n == 0 || x < 0 {
+		return 0
+	}
+	if x >= s.max {
+		return s.n
+	}
+	return s.firstIndexFrom(x + 1)
+}
+
+// NextGEQ returns the smallest member of the set that is >= x.
+// It returns false if there is no such member.
+func (s *Set) NextGEQ(x int) (int, bool) {
+	if s.n == 0 || x > s.max {
+		return 0, false
+	}
+	if x < 0 {
+		x = 0
+	}
+	i := s.firstIndexFrom(x)
+	return s.Select(i)
+}
+
+// firstIndexFrom returns the index of the smallest member that is
+// >= x, which must be in the range [0, max]. Members are found by
+// skipping straight to the bucket for the high bits of x, then
+// scanning forward through that bucket.
+func (s *Set) firstIndexFrom(x int) int {
+	h := x >> s.lowBits
+	// Bucket h starts just after the (h-1)-th zero, and every
+	// one before that position is a member with smaller high bits.
+	p := 0
+	if h > 0 {
+		p = s.highs.select0(h-1) + 1
+	}
+	i := p - h
+	for ; p < s.highs.length; p++ {
+		if !s.highs.get(p) {
+			// This zero ends bucket h, so every later member
+			// has larger high bits than x.
+			return i
+		}
+		if (p-i)<<s.lowBits|s.low(i) >= x {
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// Iter iterates through every member of the set in ascending order
+// and calls function f using the member as an argument.
+func (s *Set) Iter(f func(x int)) {
+	i := 0
+	for p := 0; i < s.n; p++ {
+		if s.highs.get(p) {
+			f((p-i)<<s.lowBits | s.low(i))
+			i++
+		}
+	}
+}
+
+// MarshalBinary encodes the set as bytes that UnmarshalBinary
+// can read back.
+func (s *Set) MarshalBinary() ([]byte, error) {
+	var data []byte
+	data = binary.AppendUvarint(data, uint64(s.n))
+	data = binary.AppendUvarint(data, uint64(s.max))
+	data = binary.AppendUvarint(data, uint64(s.lowBits))
+	for _, w := range s.lows {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	for _, w := range s.highs.words {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the set with one decoded from bytes
+// written by MarshalBinary.
+func (s *Set) UnmarshalBinary(data []byte) error {
+	var header [3]uint64
+	for i := range header {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			return InvalidFormat
+		}
+		header[i] = x
+		data = data[n:]
+	}
+	n, max, lowBits := int(header[0]), int(header[1]), uint(header[2])
+	if n < 0 || max < 0 || lowBits >= 64 {
+		return InvalidFormat
+	}
+	t := &Set{n: n, max: max, lowBits: lowBits}
+	if n == 0 {
+		t.highs = newBitVector(nil, 0)
+		*s = *t
+		return nil
+	}
+	highLength := n + max>>lowBits + 1
+	numLows := (n*int(lowBits) + 63) / 64
+	numHighs := (highLength + 63) / 64
+	if len(data) != 8*(numLows+numHighs) {
+		return InvalidFormat
+	}
+	t.lows = make([]uint64, numLows)
+	for i := range t.lows {
+		t.lows[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	data = data[8*numLows:]
+	highWords := make([]uint64, numHighs)
+	for i := range highWords {
+		highWords[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	t.highs = newBitVector(highWords, highLength)
+	if t.highs.rank1(highLength) != n || t.highs.rank1(numHighs*64) != n {
+		return InvalidFormat
+	}
+	*s = *t
+	return nil
+}
@@ -0,0 +1,123 @@
+package eliasfano
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func randomSorted(r *rand.Rand, n, max int) []int {
+	seen := make(map[int]struct{})
+	for len(seen) < n {
+		seen[r.Intn(max)] = struct{}{}
+	}
+	xs := make([]int, 0, n)
+	for x := range seen {
+		xs = append(xs, x)
+	}
+	slices.Sort(xs)
+	return xs
+}
+
+func members(s *Set) []int {
+	got := []int{}
+	s.Iter(func(x int) {
+		got = append(got, x)
+	})
+	return got
+}
+
+// check compares every query on s against a plain sorted slice.
+func check(t *testing.T, s *Set, want []int) {
+	if got := members(s); !slices.Equal(want, got) {
+		t.Fatalf("Expected members %v, got %v", want, got)
+	}
+	if s.Len() != len(want) {
+		t.Errorf("Expected length %v, got %v", len(want), s.Len())
+	}
+	max := 10
+	if len(want) > 0 {
+		max = want[len(want)-1] + 10
+	}
+	for x := -2; x < max; x++ {
+		i := sort.SearchInts(want, x)
+		has := i < len(want) && want[i] == x
+		if s.Contains(x) != has {
+			t.Fatalf("Expected Contains(%v) to be %v", x, has)
+		}
+		next, ok := s.NextGEQ(x)
+		if ok != (i < len(want)) || ok && next != want[i] {
+			t.Fatalf("NextGEQ(%v): got %v %v", x, next, ok)
+		}
+		rank := sort.SearchInts(want, x+1)
+		if got := s.Rank(x); got != rank {
+			t.Fatalf("Expected Rank(%v) to be %v, got %v", x, rank, got)
+		}
+	}
+	for i, x := range want {
+		if got, ok := s.Select(i); !ok || got != x {
+			t.Fatalf("Expected Select(%v) to be %v, got %v", i, x, got)
+		}
+	}
+	if _, ok := s.Select(len(want)); ok {
+		t.Error("Expected Select past the end to fail")
+	}
+}
+
+func TestSmall(t *testing.T) {
+	for _, want := range [][]int{
+		{},
+		{0},
+		{5},
+		{0, 1, 2, 3},
+		{3, 9, 10, 100, 1000},
+	} {
+		s, err := New(want)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		check(t, s, want)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	// Dense, sparse and in between.
+	for _, test := range []struct{ n, max int }{
+		{1000, 1200},
+		{1000, 100000},
+		{300, 5000},
+	} {
+		want := randomSorted(r, test.n, test.max)
+		s, _ := New(want)
+		check(t, s, want)
+	}
+}
+
+func TestUnsorted(t *testing.T) {
+	for _, input := range [][]int{{1, 3, 2}, {1, 1}, {-1, 2}} {
+		if _, err := New(input); err == nil {
+			t.Errorf("Expected an error for %v", input)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	want := randomSorted(r, 500, 1<<20)
+	s, _ := New(want)
+	data, _ := s.MarshalBinary()
+	got := &Set{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	check(t, got, want)
+	// Elias-Fano should beat 8 bytes per member by a wide margin.
+	if len(data) > 3*len(want) {
+		t.Errorf("Expected about 2+log2(u/n) bits per member, got %v bytes for %v members", len(data), len(want))
+	}
+	if err := got.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("Expected an error for truncated data")
+	}
+}
@@ -0,0 +1,125 @@
+// Package fenwick implements a Fenwick tree, also known as a
+// binary indexed tree, over a fixed-length array of ints.
+//
+// It is an express design decision to hard-code
+// this tree just for the int type rather than for
+// the empty interface.
+//
+// A Fenwick tree keeps running sums of an array such that changing
+// one item and summing any prefix of the array both take O(log n)
+// time, where a plain slice makes one or the other take O(n).
+// For other aggregates than sums, such as minima, and for updates
+// to whole ranges of items, see ints/segtree.
+package fenwick
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Tree holds the partial sums of the tree. sums[i-1] holds the sum
+// of the items in the half-open range [i - i&-i, i), so that any
+// prefix sum is the sum of at most log n of them.
+type Tree struct {
+	sums []int
+}
+
+// New returns a new tree of n items, all of them 0.
+func New(n int) *Tree {
+	if n < 0 {
+		panic(fmt.Sprintf("fenwick: length %d is negative", n))
+	}
+	return &Tree{sums: make([]int, n)}
+}
+
+// NewFromSlice returns a new tree holding the items of data.
+// It takes O(n) time.
+func NewFromSlice(data []int) *Tree {
+	t := &Tree{sums: make([]int, len(data))}
+	copy(t.sums, data)
+	for i := 1; i <= len(t.sums); i++ {
+		if parent := i + i&-i; parent <= len(t.sums) {
+			t.sums[parent-1] += t.sums[i-1]
+		}
+	}
+	return t
+}
+
+// Len returns the number of items in the tree.
+func (t *Tree) Len() int {
+	return len(t.sums)
+}
+
+func (t *Tree) checkIndex(i int) {
+	if i < 0 || i >= len(t.sums) {
+		panic(fmt.Sprintf("fenwick: index %d out of range [0, %d)", i, len(t.sums)))
+	}
+}
+
+// Add adds delta to item i. It panics if i is out of range.
+func (t *Tree) Add(i int, delta int) {
+	t.checkIndex(i)
+	for i++; i <= len(t.sums); i += i & -i {
+		t.sums[i-1] += delta
+	}
+}
+
+// Set sets item i to x. It panics if i is out of range.
+func (t *Tree) Set(i int, x int) {
+	t.Add(i, x-t.Get(i))
+}
+
+// Get returns item i. It panics if i is out of range.
+func (t *Tree) Get(i int) int {
+	t.checkIndex(i)
+	return t.RangeSum(i, i+1)
+}
+
+// PrefixSum returns the sum of the first i items, which is to say
+// the items in the half-open range [0, i). It panics unless
+// 0 <= i <= Len().
+func (t *Tree) PrefixSum(i int) int {
+	if i < 0 || i > len(t.sums) {
+		panic(fmt.Sprintf("fenwick: prefix length %d out of range [0, %d]", i, len(t.sums)))
+	}
+	sum := 0
+	for ; i > 0; i -= i & -i {
+		sum += t.sums[i-1]
+	}
+	return sum
+}
+
+// RangeSum returns the sum of the items in the half-open
+// range [lo, hi). It panics unless 0 <= lo <= hi <= Len().
+func (t *Tree) RangeSum(lo int, hi int) int {
+	if lo > hi {
+		panic(fmt.Sprintf("fenwick: range [%d, %d) is backwards", lo, hi))
+	}
+	return t.PrefixSum(hi) - t.PrefixSum(lo)
+}
+
+// LowerBound returns the smallest i such that PrefixSum(i) >= sum.
+// It returns false if there is no such i, because the sum of every
+// item is less than sum. The answer is only meaningful if no item
+// is negative, so that the prefix sums never decrease. It takes
+// O(log n) time, where searching with PrefixSum would take O(log² n).
+func (t *Tree) LowerBound(sum int) (int, bool) {
+	if sum <= 0 {
+		return 0, true
+	}
+	// Find the longest prefix whose sum is less than sum, by
+	// walking down the implicit tree one power of two at a time.
+	pos := 0
+	if len(t.sums) > 0 {
+		for step := 1 << (bits.Len(uint(len(t.sums))) - 1); step > 0; step >>= 1 {
+			if next := pos + step; next <= len(t.sums) && t.sums[next-1] < sum {
+				pos = next
+				sum -= t.sums[next-1]
+			}
+		}
+	}
+	if pos == len(t.sums) {
+		return 0, false
+	}
+	return pos + 1, true
+}
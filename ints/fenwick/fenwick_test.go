@@ -0,0 +1,114 @@
+package fenwick
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPrefixSum(t *testing.T) {
+	data := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	tree := NewFromSlice(data)
+	sum := 0
+	for i := 0; i <= len(data); i++ {
+		if got := tree.PrefixSum(i); got != sum {
+			t.Errorf("Expected prefix sum %v of length %v, got %v", sum, i, got)
+		}
+		if i < len(data) {
+			sum += data[i]
+		}
+	}
+	if got := tree.RangeSum(2, 5); got != 10 {
+		t.Errorf("Expected range sum 10, got %v", got)
+	}
+	tree.Add(2, -4)
+	tree.Set(7, 0)
+	if got := tree.Get(2); got != 0 {
+		t.Errorf("Expected 0, got %v", got)
+	}
+	if got := tree.PrefixSum(8); got != 31-4-6 {
+		t.Errorf("Expected %v, got %v", 31-4-6, got)
+	}
+}
+
+func TestLowerBound(t *testing.T) {
+	tree := NewFromSlice([]int{2, 0, 3, 0, 0, 5})
+	var tests = []struct {
+		sum  int
+		want int
+		ok   bool
+	}{
+		{-1, 0, true},
+		{0, 0, true},
+		{1, 1, true},
+		{2, 1, true},
+		{3, 3, true},
+		{5, 3, true},
+		{6, 6, true},
+		{10, 6, true},
+		{11, 0, false},
+	}
+	for _, test := range tests {
+		got, ok := tree.LowerBound(test.sum)
+		if got != test.want || ok != test.ok {
+			t.Errorf("Expected lower bound of %v to be %v %v, got %v %v", test.sum, test.want, test.ok, got, ok)
+		}
+	}
+	if _, ok := New(0).LowerBound(1); ok {
+		t.Error("Expected no lower bound in an empty tree")
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const n = 100
+	data := make([]int, n)
+	tree := New(n)
+	for i := 0; i < 2000; i++ {
+		j, x := r.Intn(n), r.Intn(10)
+		if r.Intn(2) == 0 {
+			data[j] += x
+			tree.Add(j, x)
+		} else {
+			data[j] = x
+			tree.Set(j, x)
+		}
+		lo := r.Intn(n + 1)
+		hi := lo + r.Intn(n+1-lo)
+		want := 0
+		for _, x := range data[lo:hi] {
+			want += x
+		}
+		if got := tree.RangeSum(lo, hi); got != want {
+			t.Fatalf("Expected range sum [%v, %v) to be %v, got %v", lo, hi, want, got)
+		}
+		sum := r.Intn(5 * n)
+		wantIndex, prefix := 0, 0
+		for wantIndex < n && prefix < sum {
+			prefix += data[wantIndex]
+			wantIndex++
+		}
+		gotIndex, ok := tree.LowerBound(sum)
+		if ok != (prefix >= sum) || ok && gotIndex != wantIndex {
+			t.Fatalf("Expected lower bound of %v to be %v, got %v %v", sum, wantIndex, gotIndex, ok)
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	tree := New(3)
+	for name, f := range map[string]func(){
+		"Add":       func() { tree.Add(3, 1) },
+		"Get":       func() { tree.Get(-1) },
+		"PrefixSum": func() { tree.PrefixSum(4) },
+		"RangeSum":  func() { tree.RangeSum(2, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected %v to panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,315 @@
+// Package idalloc implements allocators that hand out unused int
+// IDs and take them back when they are released.
+//
+// It is an express design decision to hard-code
+// these allocators just for the int type rather than for
+// the empty interface.
+//
+// IDs are drawn from [0, max). Both allocators hand out IDs that
+// have never been used by counting up from 0, and differ in which
+// released ID they hand out again first:
+//
+// LIFO hands out the most recently released ID, keeping released
+// IDs on an ints/stack. That is as cheap as it gets, and keeps
+// recently used IDs warm in whatever they index.
+//
+// LowestFirst always hands out the smallest unused ID, keeping
+// released IDs on an ints/maxheap. That keeps the IDs in use as
+// small and as densely packed as possible.
+//
+// Both keep track of which IDs are in use in an ints/bitset, so
+// releasing an ID that is not in use, such as one that has already
+// been released, is reported as an error rather than corrupting
+// the allocator.
+package idalloc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/manniwood/mmmdatastructures/ints/bitset"
+	"github.com/manniwood/mmmdatastructures/ints/maxheap"
+	"github.com/manniwood/mmmdatastructures/ints/stack"
+)
+
+var IDsExhausted = errors.New("IDs Exhausted")
+var NotAllocated = errors.New("ID Not Allocated")
+var AlreadyAllocated = errors.New("ID Already Allocated")
+var OutOfRange = errors.New("ID Out Of Range")
+
+type InvalidCountError struct {
+	msg string
+}
+
+func (e *InvalidCountError) Error() string {
+	return e.msg
+}
+
+// Allocator hands out unused IDs and takes them back.
+type Allocator interface {
+	// Allocate returns an unused ID and marks it as in use.
+	Allocate() (int, error)
+	// AllocateRange finds n consecutive unused IDs, marks them as
+	// in use, and returns the first of them.
+	AllocateRange(n int) (int, error)
+	// Release marks an ID as unused again, so that it can be
+	// handed out again. It returns NotAllocated if the ID is not
+	// in use, which catches releasing the same ID twice.
+	Release(id int) error
+	// Reserve marks a particular ID as in use, so that it is never
+	// handed out. It returns AlreadyAllocated if the ID is in use.
+	Reserve(id int) error
+}
+
+// ids holds the state shared by both allocators.
+type ids struct {
+	max   int
+	inUse *bitset.Set
+	// next is the smallest ID that has never been handed out. IDs
+	// at or above next are only in use if they were reserved, and
+	// unused IDs below next are on the free list. The free list may
+	// also hold IDs that are in use, because they were reserved or
+	// handed out by AllocateRange after they were released; those are
+	// skipped when they come off the free list.
+	next int
+	// queued holds the IDs on the free list, so that an ID that is
+	// released, reserved and released again is not put on it twice,
+	// which keeps the free list no longer than max.
+	queued *bitset.Set
+}
+
+func newIDs(max int) ids {
+	return ids{max: max, inUse: bitset.New(), queued: bitset.New()}
+}
+
+// queue puts id on the free list with push, unless it is already
+// there.
+func (a *ids) queue(id int, push func(id int) error) error {
+	if a.queued.Has(id) {
+		return nil
+	}
+	if err := push(id); err != nil {
+		return err
+	}
+	a.queued.Put(id)
+	return nil
+}
+
+// unqueued notes that id has come off the free list, and tells you
+// whether it can be handed out.
+func (a *ids) unqueued(id int) bool {
+	a.queued.Delete(id)
+	return !a.inUse.Has(id)
+}
+
+func (a *ids) checkRange(id int) error {
+	if id < 0 || id >= a.max {
+		return OutOfRange
+	}
+	return nil
+}
+
+// fresh returns the smallest never handed out ID that is not
+// reserved, and moves next past it.
+func (a *ids) fresh() (int, error) {
+	for a.next < a.max && a.inUse.Has(a.next) {
+		a.next++
+	}
+	if a.next >= a.max {
+		return 0, IDsExhausted
+	}
+	id := a.next
+	a.next++
+	a.inUse.Put(id)
+	return id, nil
+}
+
+// gap returns the first of the lowest n consecutive unused IDs
+// that are >= from.
+func (a *ids) gap(from int, n int) (int, error) {
+	start := from
+	for {
+		if start > a.max-n {
+			return 0, IDsExhausted
+		}
+		used, ok := a.inUse.NextSet(start)
+		if !ok || used >= start+n {
+			return start, nil
+		}
+		start = used + 1
+	}
+}
+
+// take marks the n IDs from start as in use and moves next past
+// them, queueing with push each unused ID below start that next
+// moves past, since those IDs must now go on the free list.
+func (a *ids) take(start int, n int, push func(id int) error) error {
+	for id := a.next; id < start; id++ {
+		if !a.inUse.Has(id) {
+			if err := a.queue(id, push); err != nil {
+				return err
+			}
+		}
+	}
+	for id := start; id < start+n; id++ {
+		a.inUse.Put(id)
+	}
+	if start+n > a.next {
+		a.next = start + n
+	}
+	return nil
+}
+
+// release marks id as unused, and tells you whether it needs to go
+// on the free list, which it does not if it is at or above next,
+// because then it was reserved rather than handed out, and will be
+// handed out again when next reaches it.
+func (a *ids) release(id int) (bool, error) {
+	if err := a.checkRange(id); err != nil {
+		return false, err
+	}
+	if !a.inUse.Has(id) {
+		return false, NotAllocated
+	}
+	a.inUse.Delete(id)
+	return id < a.next, nil
+}
+
+func (a *ids) reserve(id int) error {
+	if err := a.checkRange(id); err != nil {
+		return err
+	}
+	if a.inUse.Has(id) {
+		return AlreadyAllocated
+	}
+	// If id is on the free list, it stays there, and is
+	// skipped when it comes up because it is in use. It is not
+	// queued again if it is released before then.
+	a.inUse.Put(id)
+	return nil
+}
+
+func checkCount(n int) error {
+	if n < 1 {
+		return &InvalidCountError{
+			msg: fmt.Sprintf("count %d is zero or negative", n),
+		}
+	}
+	return nil
+}
+
+// LIFO is an Allocator that hands out the most recently
+// released ID first.
+type LIFO struct {
+	ids
+	free *stack.Stack
+}
+
+// NewLIFO returns a new LIFO allocator for IDs in [0, max).
+func NewLIFO(max int) *LIFO {
+	return &LIFO{
+		ids:  newIDs(max),
+		free: stack.New(),
+	}
+}
+
+// Allocate returns the most recently released ID, or if there is
+// none, the smallest ID that has never been handed out.
+func (a *LIFO) Allocate() (int, error) {
+	for a.free.Size() > 0 {
+		id, _ := a.free.Pop()
+		if a.unqueued(id) {
+			a.inUse.Put(id)
+			return id, nil
+		}
+	}
+	return a.fresh()
+}
+
+// AllocateRange returns the first of n consecutive IDs that have
+// never been handed out. Released IDs are not considered, since
+// finding a long enough run of them would mean searching the
+// free list.
+func (a *LIFO) AllocateRange(n int) (int, error) {
+	if err := checkCount(n); err != nil {
+		return 0, err
+	}
+	start, err := a.gap(a.next, n)
+	if err != nil {
+		return 0, err
+	}
+	return start, a.take(start, n, a.free.Push)
+}
+
+func (a *LIFO) Release(id int) error {
+	free, err := a.release(id)
+	if !free || err != nil {
+		return err
+	}
+	return a.queue(id, a.free.Push)
+}
+
+func (a *LIFO) Reserve(id int) error {
+	return a.reserve(id)
+}
+
+// LowestFirst is an Allocator that always hands out the
+// smallest unused ID.
+type LowestFirst struct {
+	ids
+	// free is a max heap of the negations of the released IDs,
+	// which makes it a min heap of the released IDs.
+	free *maxheap.MaxHeap
+}
+
+// NewLowestFirst returns a new LowestFirst allocator for IDs in [0, max).
+func NewLowestFirst(max int) *LowestFirst {
+	return &LowestFirst{
+		ids:  newIDs(max),
+		free: maxheap.New(),
+	}
+}
+
+// Allocate returns the smallest unused ID.
+func (a *LowestFirst) Allocate() (int, error) {
+	for a.free.Size() > 0 {
+		neg, _ := a.free.Delete()
+		if a.unqueued(-neg) {
+			a.inUse.Put(-neg)
+			return -neg, nil
+		}
+	}
+	// Every ID on the free heap is smaller than next, so the
+	// smallest unused ID is only at or above next if it is empty.
+	return a.fresh()
+}
+
+// AllocateRange returns the first of the lowest n consecutive
+// unused IDs. It takes time proportional to the number of IDs
+// below the ones it returns.
+func (a *LowestFirst) AllocateRange(n int) (int, error) {
+	if err := checkCount(n); err != nil {
+		return 0, err
+	}
+	start, err := a.gap(0, n)
+	if err != nil {
+		return 0, err
+	}
+	return start, a.take(start, n, a.push)
+}
+
+func (a *LowestFirst) push(id int) error {
+	return a.free.Insert(-id)
+}
+
+func (a *LowestFirst) Release(id int) error {
+	free, err := a.release(id)
+	if !free || err != nil {
+		return err
+	}
+	return a.queue(id, a.push)
+}
+
+func (a *LowestFirst) Reserve(id int) error {
+	return a.reserve(id)
+}
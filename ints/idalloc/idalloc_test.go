@@ -0,0 +1,245 @@
+package idalloc
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestLIFO(t *testing.T) {
+	a := NewLIFO(10)
+	for want := 0; want < 3; want++ {
+		if got, err := a.Allocate(); err != nil || got != want {
+			t.Errorf("Expected %v, got %v (%v)", want, got, err)
+		}
+	}
+	a.Release(0)
+	a.Release(2)
+	for _, want := range []int{2, 0, 3} {
+		if got, err := a.Allocate(); err != nil || got != want {
+			t.Errorf("Expected %v, got %v (%v)", want, got, err)
+		}
+	}
+}
+
+func TestLowestFirst(t *testing.T) {
+	a := NewLowestFirst(10)
+	for want := 0; want < 3; want++ {
+		if got, err := a.Allocate(); err != nil || got != want {
+			t.Errorf("Expected %v, got %v (%v)", want, got, err)
+		}
+	}
+	a.Release(2)
+	a.Release(0)
+	for _, want := range []int{0, 2, 3} {
+		if got, err := a.Allocate(); err != nil || got != want {
+			t.Errorf("Expected %v, got %v (%v)", want, got, err)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, a := range []Allocator{NewLIFO(2), NewLowestFirst(2)} {
+		id, _ := a.Allocate()
+		if err := a.Release(id); err != nil {
+			t.Errorf("%T: unexpected error: %v", a, err)
+		}
+		if err := a.Release(id); !errors.Is(err, NotAllocated) {
+			t.Errorf("%T: expected NotAllocated for double release, got %v", a, err)
+		}
+		if err := a.Release(1); !errors.Is(err, NotAllocated) {
+			t.Errorf("%T: expected NotAllocated for an ID never handed out, got %v", a, err)
+		}
+		if err := a.Release(2); !errors.Is(err, OutOfRange) {
+			t.Errorf("%T: expected OutOfRange, got %v", a, err)
+		}
+		if err := a.Reserve(-1); !errors.Is(err, OutOfRange) {
+			t.Errorf("%T: expected OutOfRange, got %v", a, err)
+		}
+		if err := a.Reserve(1); err != nil {
+			t.Errorf("%T: unexpected error: %v", a, err)
+		}
+		if err := a.Reserve(1); !errors.Is(err, AlreadyAllocated) {
+			t.Errorf("%T: expected AlreadyAllocated, got %v", a, err)
+		}
+		if got, err := a.Allocate(); err != nil || got != 0 {
+			t.Errorf("%T: expected 0, got %v (%v)", a, got, err)
+		}
+		if _, err := a.Allocate(); !errors.Is(err, IDsExhausted) {
+			t.Errorf("%T: expected IDsExhausted, got %v", a, err)
+		}
+		if _, err := a.AllocateRange(0); err == nil {
+			t.Errorf("%T: expected an error for a count of 0", a)
+		}
+	}
+}
+
+func TestReserve(t *testing.T) {
+	for _, a := range []Allocator{NewLIFO(10), NewLowestFirst(10)} {
+		a.Reserve(1)
+		a.Reserve(3)
+		for _, want := range []int{0, 2, 4} {
+			if got, _ := a.Allocate(); got != want {
+				t.Errorf("%T: expected %v, got %v", a, want, got)
+			}
+		}
+		// 3 was never handed out, but Allocate has moved past it,
+		// so releasing it puts it on the free list. 8 was never
+		// reached, so releasing it leaves it to be handed out in turn.
+		a.Reserve(8)
+		a.Release(8)
+		a.Release(3)
+		a.Release(1)
+		for _, want := range []int{1, 3, 5, 6, 7, 8} {
+			if got, _ := a.Allocate(); got != want {
+				t.Errorf("%T: expected %v, got %v", a, want, got)
+			}
+		}
+	}
+}
+
+// TestReserveReleaseCycles checks that reserving and releasing
+// recycled IDs over and over does not keep adding them to the free
+// list, which would grow without bound.
+func TestReserveReleaseCycles(t *testing.T) {
+	lifo, lowest := NewLIFO(100), NewLowestFirst(100)
+	freeSize := map[Allocator]func() int{
+		lifo:   lifo.free.Size,
+		lowest: lowest.free.Size,
+	}
+	for _, a := range []Allocator{lifo, lowest} {
+		for i := 0; i < 100; i++ {
+			a.Allocate()
+		}
+		for id := 0; id < 100; id++ {
+			a.Release(id)
+		}
+		for i := 0; i < 100000; i++ {
+			id := i % 100
+			if err := a.Reserve(id); err != nil {
+				t.Fatalf("%T: unexpected error reserving %v: %v", a, id, err)
+			}
+			if err := a.Release(id); err != nil {
+				t.Fatalf("%T: unexpected error releasing %v: %v", a, id, err)
+			}
+		}
+		if size := freeSize[a](); size != 100 {
+			t.Errorf("%T: expected 100 IDs on the free list, got %v", a, size)
+		}
+		for i := 0; i < 100; i++ {
+			if _, err := a.Allocate(); err != nil {
+				t.Fatalf("%T: unexpected error allocating: %v", a, err)
+			}
+		}
+		if _, err := a.Allocate(); err != IDsExhausted {
+			t.Errorf("%T: expected IDsExhausted, got %v", a, err)
+		}
+		if size := freeSize[a](); size != 0 {
+			t.Errorf("%T: expected an empty free list, got %v", a, size)
+		}
+	}
+}
+
+func TestAllocateRange(t *testing.T) {
+	var tests = []struct {
+		a    Allocator
+		want []int
+	}{
+		// LIFO only looks past the IDs it has handed out,
+		// and starts after the reserved 7.
+		{NewLIFO(20), []int{8, 11}},
+		// LowestFirst looks for the lowest gap big enough.
+		{NewLowestFirst(20), []int{1, 8}},
+	}
+	for _, test := range tests {
+		a := test.a
+		for i := 0; i < 5; i++ {
+			a.Allocate()
+		}
+		a.Reserve(7)
+		a.Release(1)
+		a.Release(2)
+		a.Release(3)
+		for i, n := range []int{3, 3} {
+			got, err := a.AllocateRange(n)
+			if err != nil || got != test.want[i] {
+				t.Errorf("%T: expected range %v to start at %v, got %v (%v)", a, i, test.want[i], got, err)
+			}
+		}
+		if _, err := a.AllocateRange(20); !errors.Is(err, IDsExhausted) {
+			t.Errorf("%T: expected IDsExhausted, got %v", a, err)
+		}
+	}
+}
+
+// TestRandom allocates and releases at random, checking that no ID
+// is handed out twice, and that every ID skipped by AllocateRange
+// can still be allocated.
+func TestRandom(t *testing.T) {
+	const max = 200
+	for _, newAllocator := range []func(int) Allocator{
+		func(max int) Allocator { return NewLIFO(max) },
+		func(max int) Allocator { return NewLowestFirst(max) },
+	} {
+		a := newAllocator(max)
+		r := rand.New(rand.NewSource(1))
+		inUse := make(map[int]bool)
+		lowestFree := func() int {
+			for id := 0; id < max; id++ {
+				if !inUse[id] {
+					return id
+				}
+			}
+			return max
+		}
+		for i := 0; i < 5000; i++ {
+			switch r.Intn(4) {
+			case 0, 1:
+				want := lowestFree()
+				id, err := a.Allocate()
+				if err != nil {
+					if want != max {
+						t.Fatalf("%T: unexpected error: %v", a, err)
+					}
+					continue
+				}
+				if inUse[id] {
+					t.Fatalf("%T: handed out %v twice", a, id)
+				}
+				if _, ok := a.(*LowestFirst); ok && id != want {
+					t.Fatalf("%T: expected %v, got %v", a, want, id)
+				}
+				inUse[id] = true
+			case 2:
+				n := 1 + r.Intn(4)
+				id, err := a.AllocateRange(n)
+				if err != nil {
+					continue
+				}
+				for j := id; j < id+n; j++ {
+					if inUse[j] {
+						t.Fatalf("%T: handed out %v twice", a, j)
+					}
+					inUse[j] = true
+				}
+			case 3:
+				id := r.Intn(max)
+				err := a.Release(id)
+				if inUse[id] != (err == nil) {
+					t.Fatalf("%T: release of %v in use=%v returned %v", a, id, inUse[id], err)
+				}
+				delete(inUse, id)
+			}
+		}
+		for len(inUse) < max {
+			id, err := a.Allocate()
+			if err != nil {
+				t.Fatalf("%T: unexpected error with %v IDs in use: %v", a, len(inUse), err)
+			}
+			if inUse[id] {
+				t.Fatalf("%T: handed out %v twice", a, id)
+			}
+			inUse[id] = true
+		}
+	}
+}
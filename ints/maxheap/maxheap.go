@@ -55,7 +55,9 @@ func NewWithCapacity(requested int) (h *MaxHeap) {
 // of the max heap cannot be grown any more to accommodate
 // the added int.
 func (h *MaxHeap) Insert(i int) error {
-	if h.size+1 > h.capacity {
+	// The backing slice does not use index 0, so it is full
+	// once size+1 reaches capacity.
+	if h.size+1 >= h.capacity {
 		newCapacity := h.capacity * 2
 		// if newCapacity became negative, we have exceeded
 		// our capacity by doing one bit-shift too far
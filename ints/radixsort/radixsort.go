@@ -0,0 +1,61 @@
+// Package radixsort implements an LSD radix sort for ints.
+//
+// It is an express design decision to hard-code
+// this sort just for the int type rather than for
+// the empty interface.
+//
+// The sort looks at the keys one byte at a time, starting with
+// the least significant byte, and does a stable counting sort on
+// each byte. That makes it O(n) for a fixed int size, at the cost
+// of a scratch slice as long as the input.
+package radixsort
+
+import "math/bits"
+
+// signBit flips the sign bit of every key, so that negative
+// numbers sort before positive numbers when the keys are
+// compared as unsigned integers.
+const signBit = uint(1) << (bits.UintSize - 1)
+
+// Sort performs a radix sort on the provided slice of int.
+// Unlike maxheap.Sort, it sorts the whole slice, including index 0.
+func Sort(data []int) {
+	if len(data) < 2 {
+		return
+	}
+	buf := make([]int, len(data))
+	src, dst := data, buf
+	for shift := 0; shift < bits.UintSize; shift += 8 {
+		var counts [256]int
+		for _, x := range src {
+			counts[digit(x, shift)]++
+		}
+		// If every key has the same byte here, this pass would not
+		// move anything, so skip it.
+		if counts[digit(src[0], shift)] == len(src) {
+			continue
+		}
+		// Turn the counts into the starting offset of each bucket.
+		offset := 0
+		for b, c := range counts {
+			counts[b] = offset
+			offset += c
+		}
+		for _, x := range src {
+			d := digit(x, shift)
+			dst[counts[d]] = x
+			counts[d]++
+		}
+		src, dst = dst, src
+	}
+	// After an odd number of passes, the sorted keys are in buf.
+	if &src[0] != &data[0] {
+		copy(data, src)
+	}
+}
+
+// digit returns the byte of x at the given shift, treating x as
+// an unsigned integer with its sign bit flipped.
+func digit(x int, shift int) uint8 {
+	return uint8((uint(x) ^ signBit) >> shift)
+}
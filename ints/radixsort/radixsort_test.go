@@ -0,0 +1,89 @@
+package radixsort
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/ints/maxheap"
+)
+
+func TestSort(t *testing.T) {
+	var tests = []struct {
+		input []int
+		want  []int
+	}{
+		{[]int{606, 243, -737, 864, 0, 663, -114, 633, 390, 143, -725, 679},
+			[]int{-737, -725, -114, 0, 143, 243, 390, 606, 633, 663, 679, 864}},
+		{[]int{math.MaxInt, -1, math.MinInt, 1, 0},
+			[]int{math.MinInt, -1, 0, 1, math.MaxInt}},
+		{[]int{2, 2, 1},
+			[]int{1, 2, 2}},
+		{[]int{0},
+			[]int{0}},
+		{nil,
+			nil},
+	}
+	for _, test := range tests {
+		Sort(test.input)
+		if !slices.Equal(test.want, test.input) {
+			t.Errorf("Expected %v, got %v", test.want, test.input)
+		}
+	}
+}
+
+func TestSortRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{10, 1000, 100000} {
+		input := make([]int, n)
+		for i := range input {
+			input[i] = int(r.Uint64())
+		}
+		want := slices.Clone(input)
+		slices.Sort(want)
+		Sort(input)
+		if !slices.Equal(want, input) {
+			t.Errorf("Random slice of length %v was not sorted", n)
+		}
+	}
+}
+
+func benchmarkInput() []int {
+	r := rand.New(rand.NewSource(1))
+	input := make([]int, 1000000)
+	for i := range input {
+		input[i] = int(r.Uint64())
+	}
+	return input
+}
+
+func BenchmarkSort(b *testing.B) {
+	input := benchmarkInput()
+	data := make([]int, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(data, input)
+		Sort(data)
+	}
+}
+
+func BenchmarkHeapSort(b *testing.B) {
+	input := benchmarkInput()
+	data := make([]int, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(data, input)
+		maxheap.Sort(data)
+	}
+}
+
+func BenchmarkSlicesSort(b *testing.B) {
+	input := benchmarkInput()
+	data := make([]int, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(data, input)
+		slices.Sort(data)
+	}
+}
@@ -0,0 +1,227 @@
+// Package rangeset implements a set of ints stored as runs of
+// consecutive ints.
+//
+// It is an express design decision to hard-code
+// this set just for the int type rather than for
+// the empty interface.
+//
+// Where ints/set would store every member separately, a RangeSet
+// stores a sorted slice of disjoint half-open ranges [Lo, Hi).
+// Ranges that overlap or touch are merged as they are added, so
+// a set of allocated ID ranges or received sequence numbers stays
+// as small as the number of gaps in it.
+//
+// Because ranges are half-open, math.MaxInt itself can never be
+// a member.
+package rangeset
+
+import "sort"
+
+// Range is the half-open range of ints [Lo, Hi).
+type Range struct {
+	Lo int
+	Hi int
+}
+
+// RangeSet holds the runs of the set, sorted, with a gap
+// of at least one int between each run and the next.
+type RangeSet struct {
+	runs []Range
+}
+
+// New returns a new empty range set.
+func New() *RangeSet {
+	return &RangeSet{}
+}
+
+// AddRange puts every int in [lo, hi) in the set.
+func (s *RangeSet) AddRange(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+	// Runs i through j-1 overlap or touch [lo, hi), so they
+	// all get merged into a single run.
+	i := sort.Search(len(s.runs), func(k int) bool { return s.runs[k].Hi >= lo })
+	j := sort.Search(len(s.runs), func(k int) bool { return s.runs[k].Lo > hi })
+	if i < j {
+		lo = min(lo, s.runs[i].Lo)
+		hi = max(hi, s.runs[j-1].Hi)
+	}
+	s.splice(i, j, Range{Lo: lo, Hi: hi})
+}
+
+// RemoveRange deletes every int in [lo, hi) from the set.
+func (s *RangeSet) RemoveRange(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+	// Runs i through j-1 overlap [lo, hi); all that is left of
+	// them is what sticks out on either side.
+	i := sort.Search(len(s.runs), func(k int) bool { return s.runs[k].Hi > lo })
+	j := sort.Search(len(s.runs), func(k int) bool { return s.runs[k].Lo >= hi })
+	if i == j {
+		return
+	}
+	var left []Range
+	if s.runs[i].Lo < lo {
+		left = append(left, Range{Lo: s.runs[i].Lo, Hi: lo})
+	}
+	if s.runs[j-1].Hi > hi {
+		left = append(left, Range{Lo: hi, Hi: s.runs[j-1].Hi})
+	}
+	s.splice(i, j, left...)
+}
+
+// splice replaces runs i through j-1 with rs.
+func (s *RangeSet) splice(i, j int, rs ...Range) {
+	tail := append([]Range(nil), s.runs[j:]...)
+	s.runs = append(append(s.runs[:i], rs...), tail...)
+}
+
+// Add puts k in the set.
+func (s *RangeSet) Add(k int) {
+	s.AddRange(k, k+1)
+}
+
+// Remove deletes k from the set.
+func (s *RangeSet) Remove(k int) {
+	s.RemoveRange(k, k+1)
+}
+
+// Contains tells you whether k is in the set.
+func (s *RangeSet) Contains(k int) bool {
+	i := sort.Search(len(s.runs), func(i int) bool { return s.runs[i].Hi > k })
+	return i < len(s.runs) && s.runs[i].Lo <= k
+}
+
+// ContainsRange tells you whether every int in [lo, hi) is in the set.
+func (s *RangeSet) ContainsRange(lo, hi int) bool {
+	if lo >= hi {
+		return true
+	}
+	i := sort.Search(len(s.runs), func(i int) bool { return s.runs[i].Hi > lo })
+	return i < len(s.runs) && s.runs[i].Lo <= lo && s.runs[i].Hi >= hi
+}
+
+// Gaps returns the ranges of ints within [lo, hi) that are not
+// in the set, in ascending order.
+func (s *RangeSet) Gaps(lo, hi int) []Range {
+	var gaps []Range
+	i := sort.Search(len(s.runs), func(i int) bool { return s.runs[i].Hi > lo })
+	for ; i < len(s.runs) && lo < hi; i++ {
+		r := s.runs[i]
+		if r.Lo >= hi {
+			break
+		}
+		if r.Lo > lo {
+			gaps = append(gaps, Range{Lo: lo, Hi: r.Lo})
+		}
+		lo = r.Hi
+	}
+	if lo < hi {
+		gaps = append(gaps, Range{Lo: lo, Hi: hi})
+	}
+	return gaps
+}
+
+// Len returns the number of runs in the set.
+func (s *RangeSet) Len() int {
+	return len(s.runs)
+}
+
+// Count returns the number of ints in the set.
+func (s *RangeSet) Count() int {
+	n := 0
+	for _, r := range s.runs {
+		n += r.Hi - r.Lo
+	}
+	return n
+}
+
+// Iter iterates through every run of the set in ascending order
+// and calls function f using the run as an argument.
+func (s *RangeSet) Iter(f func(r Range)) {
+	for _, r := range s.runs {
+		f(r)
+	}
+}
+
+// Runs returns a copy of every run of the set in ascending order.
+func (s *RangeSet) Runs() []Range {
+	return append([]Range(nil), s.runs...)
+}
+
+// Clone returns a copy of the set.
+func (s *RangeSet) Clone() *RangeSet {
+	return &RangeSet{runs: s.Runs()}
+}
+
+// Union puts every int in other into s.
+func (s *RangeSet) Union(other *RangeSet) {
+	merged := make([]Range, 0, len(s.runs)+len(other.runs))
+	i, j := 0, 0
+	for i < len(s.runs) || j < len(other.runs) {
+		// Take whichever run starts first...
+		var r Range
+		if j == len(other.runs) || i < len(s.runs) && s.runs[i].Lo <= other.runs[j].Lo {
+			r = s.runs[i]
+			i++
+		} else {
+			r = other.runs[j]
+			j++
+		}
+		// ...and merge it into the last run if they overlap or touch.
+		last := len(merged) - 1
+		if last >= 0 && r.Lo <= merged[last].Hi {
+			merged[last].Hi = max(merged[last].Hi, r.Hi)
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	s.runs = merged
+}
+
+// Intersect deletes every int from s that is not in other.
+func (s *RangeSet) Intersect(other *RangeSet) {
+	var result []Range
+	i, j := 0, 0
+	for i < len(s.runs) && j < len(other.runs) {
+		a, b := s.runs[i], other.runs[j]
+		lo, hi := max(a.Lo, b.Lo), min(a.Hi, b.Hi)
+		if lo < hi {
+			result = append(result, Range{Lo: lo, Hi: hi})
+		}
+		// Move past whichever run ends first.
+		if a.Hi < b.Hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	s.runs = result
+}
+
+// Difference deletes every int in other from s.
+func (s *RangeSet) Difference(other *RangeSet) {
+	var result []Range
+	j := 0
+	for _, r := range s.runs {
+		lo := r.Lo
+		// Skip runs of other that end before this run starts.
+		for j < len(other.runs) && other.runs[j].Hi <= lo {
+			j++
+		}
+		// Cut out every run of other that overlaps this run.
+		k := j
+		for ; k < len(other.runs) && other.runs[k].Lo < r.Hi; k++ {
+			if other.runs[k].Lo > lo {
+				result = append(result, Range{Lo: lo, Hi: other.runs[k].Lo})
+			}
+			lo = max(lo, other.runs[k].Hi)
+		}
+		if lo < r.Hi {
+			result = append(result, Range{Lo: lo, Hi: r.Hi})
+		}
+	}
+	s.runs = result
+}
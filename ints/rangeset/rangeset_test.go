@@ -0,0 +1,137 @@
+package rangeset
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/ints/set"
+)
+
+func TestAddRemove(t *testing.T) {
+	s := New()
+	s.AddRange(10, 20)
+	s.AddRange(30, 40)
+	s.AddRange(20, 25) // touches [10, 20), so it merges
+	s.Add(26)
+	want := []Range{{10, 25}, {26, 27}, {30, 40}}
+	if got := s.Runs(); !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	s.Add(25)
+	s.AddRange(5, 12)
+	want = []Range{{5, 27}, {30, 40}}
+	if got := s.Runs(); !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	s.RemoveRange(10, 32)
+	s.Remove(35)
+	want = []Range{{5, 10}, {32, 35}, {36, 40}}
+	if got := s.Runs(); !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if s.Count() != 12 || s.Len() != 3 {
+		t.Errorf("Expected count 12 in 3 runs, got %v in %v", s.Count(), s.Len())
+	}
+	if !s.Contains(5) || s.Contains(10) || s.Contains(35) || !s.Contains(39) {
+		t.Error("Contains was not as expected")
+	}
+	if !s.ContainsRange(36, 40) || s.ContainsRange(34, 37) {
+		t.Error("ContainsRange was not as expected")
+	}
+}
+
+func TestGaps(t *testing.T) {
+	s := New()
+	s.AddRange(10, 20)
+	s.AddRange(30, 40)
+	var tests = []struct {
+		lo, hi int
+		want   []Range
+	}{
+		{0, 50, []Range{{0, 10}, {20, 30}, {40, 50}}},
+		{15, 35, []Range{{20, 30}}},
+		{12, 18, nil},
+		{20, 30, []Range{{20, 30}}},
+		{45, 50, []Range{{45, 50}}},
+	}
+	for _, test := range tests {
+		if got := s.Gaps(test.lo, test.hi); !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Gaps(%v, %v): expected %v, got %v", test.lo, test.hi, test.want, got)
+		}
+	}
+}
+
+// randomSet builds a range set and the equivalent ints/set.Set
+// out of random adds and removes.
+func randomSet(r *rand.Rand) (*RangeSet, set.Set) {
+	s := New()
+	m := set.New()
+	for i := 0; i < 50; i++ {
+		lo := r.Intn(200)
+		hi := lo + r.Intn(20)
+		if r.Intn(3) == 0 {
+			s.RemoveRange(lo, hi)
+			for k := lo; k < hi; k++ {
+				m.Delete(k)
+			}
+		} else {
+			s.AddRange(lo, hi)
+			for k := lo; k < hi; k++ {
+				m.Put(k)
+			}
+		}
+	}
+	return s, m
+}
+
+func checkSame(t *testing.T, what string, s *RangeSet, m set.Set) {
+	for k := -5; k < 230; k++ {
+		if s.Contains(k) != m.Has(k) {
+			t.Fatalf("%v: expected Contains(%v) to be %v", what, k, m.Has(k))
+		}
+	}
+	if s.Count() != len(m) {
+		t.Fatalf("%v: expected count %v, got %v", what, len(m), s.Count())
+	}
+	runs := s.Runs()
+	for i := 1; i < len(runs); i++ {
+		if runs[i].Lo <= runs[i-1].Hi {
+			t.Fatalf("%v: runs %v and %v should have been merged", what, runs[i-1], runs[i])
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		a, am := randomSet(r)
+		b, bm := randomSet(r)
+		checkSame(t, "a", a, am)
+
+		union := set.New()
+		inter := set.New()
+		diff := set.New()
+		for k := range am {
+			union.Put(k)
+			if bm.Has(k) {
+				inter.Put(k)
+			} else {
+				diff.Put(k)
+			}
+		}
+		for k := range bm {
+			union.Put(k)
+		}
+
+		u := a.Clone()
+		u.Union(b)
+		checkSame(t, "union", u, union)
+		i := a.Clone()
+		i.Intersect(b)
+		checkSame(t, "intersect", i, inter)
+		d := a.Clone()
+		d.Difference(b)
+		checkSame(t, "difference", d, diff)
+	}
+}
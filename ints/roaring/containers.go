@@ -0,0 +1,476 @@
+package roaring
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// arrayMaxSize is the largest number of values an array container
+// holds; any more and a bitmap container is smaller.
+const arrayMaxSize = 4096
+
+// bitmapWords is the number of 64-bit words needed to hold one bit
+// for each of the 65536 values in a chunk.
+const bitmapWords = 1024
+
+// container holds the low 16 bits of the members of one chunk of
+// the bitmap. Methods that can change the container's size may
+// return a different kind of container that better fits the new
+// contents, so callers must always use the returned container.
+type container interface {
+	add(v uint16) container
+	remove(v uint16) container
+	contains(v uint16) bool
+	cardinality() int
+	// rank returns how many values are <= v.
+	rank(v uint16) int
+	// selectAt returns the i-th smallest value, counting from 0.
+	selectAt(i int) uint16
+	iter(f func(v uint16))
+	// toBitmap returns a new bitmap container with the same values.
+	toBitmap() *bitmapContainer
+	clone() container
+}
+
+// arrayContainer holds up to arrayMaxSize values in a sorted slice.
+type arrayContainer struct {
+	values []uint16
+}
+
+func (c *arrayContainer) add(v uint16) container {
+	i, found := slices.BinarySearch(c.values, v)
+	if found {
+		return c
+	}
+	if len(c.values) >= arrayMaxSize {
+		b := c.toBitmap()
+		return b.add(v)
+	}
+	c.values = slices.Insert(c.values, i, v)
+	return c
+}
+
+func (c *arrayContainer) remove(v uint16) container {
+	i, found := slices.BinarySearch(c.values, v)
+	if found {
+		c.values = slices.Delete(c.values, i, i+1)
+	}
+	return c
+}
+
+func (c *arrayContainer) contains(v uint16) bool {
+	_, found := slices.BinarySearch(c.values, v)
+	return found
+}
+
+func (c *arrayContainer) cardinality() int {
+	return len(c.values)
+}
+
+func (c *arrayContainer) rank(v uint16) int {
+	i, found := slices.BinarySearch(c.values, v)
+	if found {
+		return i + 1
+	}
+	return i
+}
+
+func (c *arrayContainer) selectAt(i int) uint16 {
+	return c.values[i]
+}
+
+func (c *arrayContainer) iter(f func(v uint16)) {
+	for _, v := range c.values {
+		f(v)
+	}
+}
+
+func (c *arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, v := range c.values {
+		b.words[v/64] |= 1 << (v % 64)
+	}
+	b.card = len(c.values)
+	return b
+}
+
+func (c *arrayContainer) clone() container {
+	return &arrayContainer{values: slices.Clone(c.values)}
+}
+
+// bitmapContainer holds more than arrayMaxSize values as one bit
+// per possible value.
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (c *bitmapContainer) add(v uint16) container {
+	mask := uint64(1) << (v % 64)
+	if c.words[v/64]&mask == 0 {
+		c.words[v/64] |= mask
+		c.card++
+	}
+	return c
+}
+
+func (c *bitmapContainer) remove(v uint16) container {
+	mask := uint64(1) << (v % 64)
+	if c.words[v/64]&mask != 0 {
+		c.words[v/64] &^= mask
+		c.card--
+	}
+	if c.card <= arrayMaxSize {
+		return c.toArray()
+	}
+	return c
+}
+
+func (c *bitmapContainer) contains(v uint16) bool {
+	return c.words[v/64]&(1<<(v%64)) != 0
+}
+
+func (c *bitmapContainer) cardinality() int {
+	return c.card
+}
+
+func (c *bitmapContainer) rank(v uint16) int {
+	n := 0
+	w := int(v / 64)
+	for i := 0; i < w; i++ {
+		n += bits.OnesCount64(c.words[i])
+	}
+	// Shifting 2 left by 63 overflows to 0, and 0-1 is all ones,
+	// which is the right mask for the last bit of a word.
+	mask := (uint64(2) << (v % 64)) - 1
+	return n + bits.OnesCount64(c.words[w]&mask)
+}
+
+func (c *bitmapContainer) selectAt(i int) uint16 {
+	for w, word := range c.words {
+		n := bits.OnesCount64(word)
+		if i >= n {
+			i -= n
+			continue
+		}
+		// Clear the i lowest set bits; the lowest remaining
+		// set bit is the one we want.
+		for ; i > 0; i-- {
+			word &= word - 1
+		}
+		return uint16(w*64 + bits.TrailingZeros64(word))
+	}
+	panic("roaring: select out of range")
+}
+
+func (c *bitmapContainer) iter(f func(v uint16)) {
+	for w, word := range c.words {
+		for word != 0 {
+			f(uint16(w*64 + bits.TrailingZeros64(word)))
+			word &= word - 1
+		}
+	}
+}
+
+func (c *bitmapContainer) toBitmap() *bitmapContainer {
+	b := *c
+	return &b
+}
+
+func (c *bitmapContainer) clone() container {
+	return c.toBitmap()
+}
+
+// toArray returns an array container with the same values.
+func (c *bitmapContainer) toArray() *arrayContainer {
+	values := make([]uint16, 0, c.card)
+	c.iter(func(v uint16) {
+		values = append(values, v)
+	})
+	return &arrayContainer{values: values}
+}
+
+// recount recomputes the cardinality after the words have been
+// changed directly, and returns whichever kind of container best
+// fits the result.
+func (c *bitmapContainer) recount() container {
+	c.card = 0
+	for _, word := range c.words {
+		c.card += bits.OnesCount64(word)
+	}
+	if c.card <= arrayMaxSize {
+		return c.toArray()
+	}
+	return c
+}
+
+// interval is a run of consecutive values from start to last inclusive.
+type interval struct {
+	start uint16
+	last  uint16
+}
+
+// runContainer holds values as sorted, non-overlapping,
+// non-adjacent runs. It is only created by RunOptimize or
+// by reading a serialised bitmap.
+type runContainer struct {
+	runs []interval
+}
+
+// search returns the index of the first run that starts after v.
+func (c *runContainer) search(v uint16) int {
+	lo, hi := 0, len(c.runs)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if c.runs[mid].start <= v {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+func (c *runContainer) add(v uint16) container {
+	i := c.search(v)
+	if i > 0 && c.runs[i-1].last >= v {
+		return c
+	}
+	joinsPrev := i > 0 && c.runs[i-1].last+1 == v
+	joinsNext := i < len(c.runs) && v < 0xFFFF && c.runs[i].start == v+1
+	switch {
+	case joinsPrev && joinsNext:
+		c.runs[i-1].last = c.runs[i].last
+		c.runs = slices.Delete(c.runs, i, i+1)
+	case joinsPrev:
+		c.runs[i-1].last = v
+	case joinsNext:
+		c.runs[i].start = v
+	default:
+		c.runs = slices.Insert(c.runs, i, interval{start: v, last: v})
+	}
+	return c
+}
+
+func (c *runContainer) remove(v uint16) container {
+	i := c.search(v) - 1
+	if i < 0 || c.runs[i].last < v {
+		return c
+	}
+	r := c.runs[i]
+	switch {
+	case r.start == v && r.last == v:
+		c.runs = slices.Delete(c.runs, i, i+1)
+	case r.start == v:
+		c.runs[i].start++
+	case r.last == v:
+		c.runs[i].last--
+	default:
+		// Split the run in two around v.
+		c.runs[i].last = v - 1
+		c.runs = slices.Insert(c.runs, i+1, interval{start: v + 1, last: r.last})
+	}
+	return c
+}
+
+func (c *runContainer) contains(v uint16) bool {
+	i := c.search(v)
+	return i > 0 && c.runs[i-1].last >= v
+}
+
+func (c *runContainer) cardinality() int {
+	n := 0
+	for _, r := range c.runs {
+		n += int(r.last-r.start) + 1
+	}
+	return n
+}
+
+func (c *runContainer) rank(v uint16) int {
+	n := 0
+	for _, r := range c.runs {
+		if r.start > v {
+			break
+		}
+		if r.last <= v {
+			n += int(r.last-r.start) + 1
+		} else {
+			n += int(v-r.start) + 1
+			break
+		}
+	}
+	return n
+}
+
+func (c *runContainer) selectAt(i int) uint16 {
+	for _, r := range c.runs {
+		n := int(r.last-r.start) + 1
+		if i < n {
+			return r.start + uint16(i)
+		}
+		i -= n
+	}
+	panic("roaring: select out of range")
+}
+
+func (c *runContainer) iter(f func(v uint16)) {
+	for _, r := range c.runs {
+		for v := int(r.start); v <= int(r.last); v++ {
+			f(uint16(v))
+		}
+	}
+}
+
+func (c *runContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, r := range c.runs {
+		for v := int(r.start); v <= int(r.last); v++ {
+			b.words[v/64] |= 1 << (v % 64)
+		}
+	}
+	b.card = c.cardinality()
+	return b
+}
+
+func (c *runContainer) clone() container {
+	return &runContainer{runs: slices.Clone(c.runs)}
+}
+
+// countRuns returns how many runs of consecutive values
+// the container holds.
+func countRuns(c container) int {
+	if r, ok := c.(*runContainer); ok {
+		return len(r.runs)
+	}
+	n := 0
+	prev := -2
+	c.iter(func(v uint16) {
+		if int(v) != prev+1 {
+			n++
+		}
+		prev = int(v)
+	})
+	return n
+}
+
+// toRuns returns a run container with the same values.
+func toRuns(c container) *runContainer {
+	r := &runContainer{}
+	c.iter(func(v uint16) {
+		last := len(r.runs) - 1
+		if last >= 0 && int(r.runs[last].last)+1 == int(v) {
+			r.runs[last].last = v
+		} else {
+			r.runs = append(r.runs, interval{start: v, last: v})
+		}
+	})
+	return r
+}
+
+// serializedSize returns how many bytes the container takes up
+// in the Roaring format.
+func serializedSize(c container) int {
+	switch c := c.(type) {
+	case *runContainer:
+		return 2 + 4*len(c.runs)
+	case *bitmapContainer:
+		return 8 * bitmapWords
+	default:
+		return 2 * c.cardinality()
+	}
+}
+
+// optimize returns whichever of an array, bitmap or run container
+// holding the same values is smallest when serialised.
+func optimize(c container) container {
+	card := c.cardinality()
+	runSize := 2 + 4*countRuns(c)
+	size := 8 * bitmapWords
+	if card <= arrayMaxSize {
+		size = 2 * card
+	}
+	if runSize < size {
+		return toRuns(c)
+	}
+	if _, ok := c.(*runContainer); !ok {
+		return c
+	}
+	if card <= arrayMaxSize {
+		return c.toBitmap().toArray()
+	}
+	return c.toBitmap()
+}
+
+// union returns a new container holding the values in a or b.
+func union(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	if aIsArray && bIsArray && len(aa.values)+len(ba.values) <= arrayMaxSize {
+		values := make([]uint16, 0, len(aa.values)+len(ba.values))
+		i, j := 0, 0
+		for i < len(aa.values) && j < len(ba.values) {
+			switch {
+			case aa.values[i] < ba.values[j]:
+				values = append(values, aa.values[i])
+				i++
+			case aa.values[i] > ba.values[j]:
+				values = append(values, ba.values[j])
+				j++
+			default:
+				values = append(values, aa.values[i])
+				i++
+				j++
+			}
+		}
+		values = append(values, aa.values[i:]...)
+		values = append(values, ba.values[j:]...)
+		return &arrayContainer{values: values}
+	}
+	x := a.toBitmap()
+	y := b.toBitmap()
+	for i := range x.words {
+		x.words[i] |= y.words[i]
+	}
+	return x.recount()
+}
+
+// intersect returns a new container holding the values in both a and b.
+func intersect(a, b container) container {
+	if aa, ok := a.(*arrayContainer); ok {
+		return filter(aa, b, true)
+	}
+	if ba, ok := b.(*arrayContainer); ok {
+		return filter(ba, a, true)
+	}
+	x := a.toBitmap()
+	y := b.toBitmap()
+	for i := range x.words {
+		x.words[i] &= y.words[i]
+	}
+	return x.recount()
+}
+
+// difference returns a new container holding the values in a but not b.
+func difference(a, b container) container {
+	if aa, ok := a.(*arrayContainer); ok {
+		return filter(aa, b, false)
+	}
+	x := a.toBitmap()
+	y := b.toBitmap()
+	for i := range x.words {
+		x.words[i] &^= y.words[i]
+	}
+	return x.recount()
+}
+
+// filter returns a new array container holding the values of a
+// for which b.contains returns keep.
+func filter(a *arrayContainer, b container, keep bool) container {
+	values := make([]uint16, 0, len(a.values))
+	for _, v := range a.values {
+		if b.contains(v) == keep {
+			values = append(values, v)
+		}
+	}
+	return &arrayContainer{values: values}
+}
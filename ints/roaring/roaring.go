@@ -0,0 +1,264 @@
+// Package roaring implements a compressed set of 32-bit ints
+// as a Roaring bitmap.
+//
+// It is an express design decision to hard-code
+// this set just for the uint32 type rather than for
+// the empty interface.
+//
+// The set is split into chunks of 65536 ints that share the same
+// high 16 bits. Each chunk that has any members stores their low 16
+// bits in whichever kind of container suits it best: a sorted array
+// when the chunk is sparse, a bitmap when it is dense, or a list of
+// runs when its members are mostly consecutive. This keeps the set
+// small whether it is sparse, dense, or a mix of the two, where
+// ints/set would spend a map entry on every member and ints/bitset
+// a bit on every int up to the largest member.
+//
+// Bitmaps can be written and read in the standard Roaring
+// serialisation format, so they can be exchanged with the Roaring
+// implementations for other languages.
+package roaring
+
+import (
+	"slices"
+)
+
+// Bitmap holds the chunks of the set. keys holds the high 16 bits
+// of each chunk in ascending order, and containers[i] holds the low
+// 16 bits of the members of chunk keys[i]. Chunks with no members
+// are not stored.
+type Bitmap struct {
+	keys       []uint16
+	containers []container
+}
+
+// New returns a new empty bitmap.
+func New() *Bitmap {
+	return &Bitmap{}
+}
+
+func split(x uint32) (uint16, uint16) {
+	return uint16(x >> 16), uint16(x)
+}
+
+// find returns the index of the container for key, or -1.
+func (b *Bitmap) find(key uint16) int {
+	i, found := slices.BinarySearch(b.keys, key)
+	if !found {
+		return -1
+	}
+	return i
+}
+
+func (b *Bitmap) Has(x uint32) bool {
+	key, low := split(x)
+	i := b.find(key)
+	return i >= 0 && b.containers[i].contains(low)
+}
+
+func (b *Bitmap) Put(x uint32) {
+	key, low := split(x)
+	i, found := slices.BinarySearch(b.keys, key)
+	if found {
+		b.containers[i] = b.containers[i].add(low)
+		return
+	}
+	b.keys = slices.Insert(b.keys, i, key)
+	b.containers = slices.Insert(b.containers, i, container(&arrayContainer{values: []uint16{low}}))
+}
+
+func (b *Bitmap) Delete(x uint32) {
+	key, low := split(x)
+	i := b.find(key)
+	if i < 0 {
+		return
+	}
+	b.containers[i] = b.containers[i].remove(low)
+	if b.containers[i].cardinality() == 0 {
+		b.keys = slices.Delete(b.keys, i, i+1)
+		b.containers = slices.Delete(b.containers, i, i+1)
+	}
+}
+
+func (b *Bitmap) PutSlice(xs []uint32) {
+	for _, x := range xs {
+		b.Put(x)
+	}
+}
+
+// Count returns the number of members of the set.
+func (b *Bitmap) Count() uint64 {
+	var n uint64
+	for _, c := range b.containers {
+		n += uint64(c.cardinality())
+	}
+	return n
+}
+
+// Rank returns the number of members of the set that are <= x.
+func (b *Bitmap) Rank(x uint32) uint64 {
+	key, low := split(x)
+	var n uint64
+	for i, k := range b.keys {
+		if k > key {
+			break
+		}
+		if k < key {
+			n += uint64(b.containers[i].cardinality())
+		} else {
+			n += uint64(b.containers[i].rank(low))
+		}
+	}
+	return n
+}
+
+// Select returns the i-th smallest member of the set, counting
+// from 0. It returns false if the set has i or fewer members.
+func (b *Bitmap) Select(i uint64) (uint32, bool) {
+	for j, c := range b.containers {
+		card := uint64(c.cardinality())
+		if i < card {
+			return uint32(b.keys[j])<<16 | uint32(c.selectAt(int(i))), true
+		}
+		i -= card
+	}
+	return 0, false
+}
+
+// Iter iterates through every member of the set in ascending order
+// and calls function f using the member as an argument.
+func (b *Bitmap) Iter(f func(x uint32)) {
+	for i, c := range b.containers {
+		high := uint32(b.keys[i]) << 16
+		c.iter(func(v uint16) {
+			f(high | uint32(v))
+		})
+	}
+}
+
+// ToSlice returns every member of the set in ascending order.
+func (b *Bitmap) ToSlice() []uint32 {
+	xs := make([]uint32, 0, b.Count())
+	b.Iter(func(x uint32) {
+		xs = append(xs, x)
+	})
+	return xs
+}
+
+// Clone returns a copy of the set.
+func (b *Bitmap) Clone() *Bitmap {
+	c := &Bitmap{
+		keys:       slices.Clone(b.keys),
+		containers: make([]container, len(b.containers)),
+	}
+	for i, cont := range b.containers {
+		c.containers[i] = cont.clone()
+	}
+	return c
+}
+
+// Equals tells you whether two sets have the same members.
+func (b *Bitmap) Equals(other *Bitmap) bool {
+	if !slices.Equal(b.keys, other.keys) {
+		return false
+	}
+	for i, c := range b.containers {
+		o := other.containers[i]
+		if c.cardinality() != o.cardinality() {
+			return false
+		}
+		equal := true
+		c.iter(func(v uint16) {
+			if equal && !o.contains(v) {
+				equal = false
+			}
+		})
+		if !equal {
+			return false
+		}
+	}
+	return true
+}
+
+// Union puts every member of other into b.
+func (b *Bitmap) Union(other *Bitmap) {
+	var keys []uint16
+	var containers []container
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || i < len(b.keys) && b.keys[i] < other.keys[j]:
+			keys = append(keys, b.keys[i])
+			containers = append(containers, b.containers[i])
+			i++
+		case i == len(b.keys) || other.keys[j] < b.keys[i]:
+			keys = append(keys, other.keys[j])
+			containers = append(containers, other.containers[j].clone())
+			j++
+		default:
+			keys = append(keys, b.keys[i])
+			containers = append(containers, union(b.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	b.keys = keys
+	b.containers = containers
+}
+
+// Intersect deletes every member of b that is not in other.
+func (b *Bitmap) Intersect(other *Bitmap) {
+	var keys []uint16
+	var containers []container
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(other.keys) {
+		switch {
+		case b.keys[i] < other.keys[j]:
+			i++
+		case other.keys[j] < b.keys[i]:
+			j++
+		default:
+			c := intersect(b.containers[i], other.containers[j])
+			if c.cardinality() > 0 {
+				keys = append(keys, b.keys[i])
+				containers = append(containers, c)
+			}
+			i++
+			j++
+		}
+	}
+	b.keys = keys
+	b.containers = containers
+}
+
+// Difference deletes every member of other from b.
+func (b *Bitmap) Difference(other *Bitmap) {
+	var keys []uint16
+	var containers []container
+	j := 0
+	for i, key := range b.keys {
+		for j < len(other.keys) && other.keys[j] < key {
+			j++
+		}
+		c := b.containers[i]
+		if j < len(other.keys) && other.keys[j] == key {
+			c = difference(c, other.containers[j])
+		}
+		if c.cardinality() > 0 {
+			keys = append(keys, key)
+			containers = append(containers, c)
+		}
+	}
+	b.keys = keys
+	b.containers = containers
+}
+
+// RunOptimize converts each chunk to whichever kind of container
+// is smallest, which may be a list of runs. It is worth calling
+// once a set is built and before it is serialised, especially if
+// its members are mostly consecutive.
+func (b *Bitmap) RunOptimize() {
+	for i, c := range b.containers {
+		b.containers[i] = optimize(c)
+	}
+}
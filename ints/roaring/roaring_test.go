@@ -0,0 +1,223 @@
+package roaring
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test(t *testing.T) {
+	s := New()
+	s.PutSlice([]uint32{1, 2, 3})
+	for i := uint32(4); i <= 6; i++ {
+		s.Put(i)
+	}
+	for i := uint32(1); i <= 6; i++ {
+		if !s.Has(i) {
+			t.Errorf("Expected %v to be in the set", i)
+		}
+	}
+	for i := uint32(4); i <= 6; i++ {
+		s.Delete(i)
+	}
+	for i := uint32(4); i <= 6; i++ {
+		if s.Has(i) {
+			t.Errorf("Did not expect %v to be in the set", i)
+		}
+	}
+	s.Delete(1 << 30)
+	if s.Count() != 3 {
+		t.Errorf("Expected count 3, got %v", s.Count())
+	}
+}
+
+// mixed returns a set with a sparse chunk, a dense chunk, a chunk of
+// consecutive runs, and members at both ends of the range of uint32,
+// along with its members in order.
+func mixed() (*Bitmap, []uint32) {
+	r := rand.New(rand.NewSource(1))
+	var want []uint32
+	for i := 0; i < 100; i++ {
+		want = append(want, uint32(r.Intn(1<<16)))
+	}
+	for i := 0; i < 20000; i++ {
+		want = append(want, 1<<16+uint32(r.Intn(1<<16)))
+	}
+	for i := uint32(5 << 16); i < 5<<16+10000; i++ {
+		if i%1000 != 0 {
+			want = append(want, i)
+		}
+	}
+	want = append(want, 0, 0xFFFFFFFF, 0xFFFF0000)
+	slices.Sort(want)
+	want = slices.Compact(want)
+	s := New()
+	s.PutSlice(want)
+	return s, want
+}
+
+func TestIterRankSelect(t *testing.T) {
+	s, want := mixed()
+	for _, optimize := range []bool{false, true} {
+		if optimize {
+			s.RunOptimize()
+		}
+		if got := s.ToSlice(); !slices.Equal(want, got) {
+			t.Fatalf("optimize=%v: members were not as expected", optimize)
+		}
+		if s.Count() != uint64(len(want)) {
+			t.Errorf("optimize=%v: expected count %v, got %v", optimize, len(want), s.Count())
+		}
+		for i := 0; i < len(want); i += 97 {
+			if got := s.Rank(want[i]); got != uint64(i+1) {
+				t.Errorf("optimize=%v: expected rank of %v to be %v, got %v", optimize, want[i], i+1, got)
+			}
+			if got, ok := s.Select(uint64(i)); !ok || got != want[i] {
+				t.Errorf("optimize=%v: expected select %v to be %v, got %v", optimize, i, want[i], got)
+			}
+		}
+		if _, ok := s.Select(uint64(len(want))); ok {
+			t.Errorf("optimize=%v: expected select past the end to fail", optimize)
+		}
+	}
+}
+
+func TestDeleteConverts(t *testing.T) {
+	s := New()
+	for i := uint32(0); i < 5000; i++ {
+		s.Put(i)
+	}
+	if _, ok := s.containers[0].(*bitmapContainer); !ok {
+		t.Error("Expected a dense chunk to be a bitmap container")
+	}
+	for i := uint32(0); i < 1000; i++ {
+		s.Delete(i)
+	}
+	if _, ok := s.containers[0].(*arrayContainer); !ok {
+		t.Error("Expected a sparse chunk to be an array container")
+	}
+	s.RunOptimize()
+	if _, ok := s.containers[0].(*runContainer); !ok {
+		t.Error("Expected a run of members to be a run container")
+	}
+	s.Delete(2000)
+	s.Put(5000)
+	s.Put(999)
+	want := []uint32{999}
+	for i := uint32(1000); i <= 5000; i++ {
+		if i != 2000 {
+			want = append(want, i)
+		}
+	}
+	if got := s.ToSlice(); !slices.Equal(want, got) {
+		t.Error("Run container members were not as expected after Put and Delete")
+	}
+}
+
+func TestAlgebra(t *testing.T) {
+	a, aMembers := mixed()
+	b := New()
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 30000; i++ {
+		b.Put(uint32(r.Intn(6 << 16)))
+	}
+	bMembers := b.ToSlice()
+	has := func(xs []uint32, x uint32) bool {
+		_, found := slices.BinarySearch(xs, x)
+		return found
+	}
+	var union, inter, diff []uint32
+	for _, x := range aMembers {
+		if has(bMembers, x) {
+			inter = append(inter, x)
+		} else {
+			diff = append(diff, x)
+		}
+	}
+	union = append(slices.Clone(aMembers), bMembers...)
+	slices.Sort(union)
+	union = slices.Compact(union)
+
+	for _, optimize := range []bool{false, true} {
+		x, y := a.Clone(), b.Clone()
+		if optimize {
+			x.RunOptimize()
+			y.RunOptimize()
+		}
+		u := x.Clone()
+		u.Union(y)
+		if !slices.Equal(union, u.ToSlice()) {
+			t.Errorf("optimize=%v: union was not as expected", optimize)
+		}
+		i := x.Clone()
+		i.Intersect(y)
+		if !slices.Equal(inter, i.ToSlice()) {
+			t.Errorf("optimize=%v: intersection was not as expected", optimize)
+		}
+		d := x.Clone()
+		d.Difference(y)
+		if !slices.Equal(diff, d.ToSlice()) {
+			t.Errorf("optimize=%v: difference was not as expected", optimize)
+		}
+	}
+	if !slices.Equal(aMembers, a.ToSlice()) {
+		t.Error("Set algebra on a clone should not change the original")
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	s, _ := mixed()
+	for _, optimize := range []bool{false, true} {
+		if optimize {
+			s.RunOptimize()
+		}
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got := New()
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !s.Equals(got) {
+			t.Errorf("optimize=%v: set did not survive serialisation", optimize)
+		}
+	}
+	if err := New().UnmarshalBinary([]byte{1, 2, 3, 4}); err == nil {
+		t.Error("Expected an error for an unknown cookie")
+	}
+	data, _ := s.MarshalBinary()
+	if err := New().UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("Expected an error for truncated data")
+	}
+}
+
+// TestSerializeFormat checks the exact bytes written against the
+// Roaring format specification, for a bitmap without runs and
+// one with runs.
+func TestSerializeFormat(t *testing.T) {
+	s := New()
+	s.PutSlice([]uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 1<<16 + 5})
+	var buf bytes.Buffer
+	s.WriteTo(&buf)
+	want := "3a300000" + "02000000" + // cookie, 2 containers
+		"0000" + "0900" + "0100" + "0000" + // keys and cardinalities - 1
+		"18000000" + "2c000000" + // offsets
+		"0100020003000400050006000700080009000a00" + "0500" // values
+	if got := hex.EncodeToString(buf.Bytes()); got != want {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	s.RunOptimize()
+	buf.Reset()
+	s.WriteTo(&buf)
+	want = "3b300100" + "01" + // cookie with 2 containers, run flags
+		"0000" + "0900" + "0100" + "0000" + // keys and cardinalities - 1
+		"0100" + "0100" + "0900" + // one run of 10 starting at 1
+		"0500" // values
+	if got := hex.EncodeToString(buf.Bytes()); got != want {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
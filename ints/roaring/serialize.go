@@ -0,0 +1,221 @@
+package roaring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// These cookies begin a serialised bitmap, and tell the reader
+// whether any of its containers are run containers. See
+// https://github.com/RoaringBitmap/RoaringFormatSpec for the format.
+const (
+	serialCookieNoRunContainer = 12346
+	serialCookie               = 12347
+	// noOffsetThreshold is the number of containers below which a
+	// bitmap with run containers leaves out the offset header.
+	noOffsetThreshold = 4
+)
+
+var InvalidFormat = errors.New("Invalid Roaring Format")
+
+// WriteTo writes the bitmap to w in the standard Roaring format.
+func (b *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	n := len(b.containers)
+	hasRun := false
+	for _, c := range b.containers {
+		if _, ok := c.(*runContainer); ok {
+			hasRun = true
+			break
+		}
+	}
+
+	// Cookie, and which containers are runs.
+	headerSize := 0
+	if hasRun {
+		binary.Write(&buf, binary.LittleEndian, uint32(serialCookie)|uint32(n-1)<<16)
+		flags := make([]byte, (n+7)/8)
+		for i, c := range b.containers {
+			if _, ok := c.(*runContainer); ok {
+				flags[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf.Write(flags)
+		headerSize = 4 + len(flags)
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(serialCookieNoRunContainer))
+		binary.Write(&buf, binary.LittleEndian, uint32(n))
+		headerSize = 8
+	}
+
+	// Key and cardinality of each container.
+	for i, c := range b.containers {
+		binary.Write(&buf, binary.LittleEndian, b.keys[i])
+		binary.Write(&buf, binary.LittleEndian, uint16(c.cardinality()-1))
+	}
+	headerSize += 4 * n
+
+	// Where each container starts.
+	if !hasRun || n >= noOffsetThreshold {
+		offset := headerSize + 4*n
+		for _, c := range b.containers {
+			binary.Write(&buf, binary.LittleEndian, uint32(offset))
+			offset += serializedSize(c)
+		}
+	}
+
+	// The containers themselves.
+	for _, c := range b.containers {
+		switch c := c.(type) {
+		case *runContainer:
+			binary.Write(&buf, binary.LittleEndian, uint16(len(c.runs)))
+			for _, r := range c.runs {
+				binary.Write(&buf, binary.LittleEndian, r.start)
+				binary.Write(&buf, binary.LittleEndian, r.last-r.start)
+			}
+		case *bitmapContainer:
+			binary.Write(&buf, binary.LittleEndian, c.words[:])
+		case *arrayContainer:
+			binary.Write(&buf, binary.LittleEndian, c.values)
+		}
+	}
+	written, err := w.Write(buf.Bytes())
+	return int64(written), err
+}
+
+// ReadFrom replaces the contents of the bitmap with a bitmap read
+// from r in the standard Roaring format.
+func (b *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	keys, containers, err := readBitmap(cr)
+	if err != nil {
+		return cr.n, err
+	}
+	b.keys = keys
+	b.containers = containers
+	return cr.n, nil
+}
+
+// MarshalBinary returns the bitmap in the standard Roaring format.
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := b.WriteTo(&buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary replaces the contents of the bitmap with a
+// bitmap in the standard Roaring format.
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	_, err := b.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func readBitmap(r io.Reader) ([]uint16, []container, error) {
+	read := func(data any) error {
+		err := binary.Read(r, binary.LittleEndian, data)
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	var cookie uint32
+	if err := read(&cookie); err != nil {
+		return nil, nil, err
+	}
+	var n int
+	var runFlags []byte
+	switch {
+	case cookie&0xFFFF == serialCookie:
+		n = int(cookie>>16) + 1
+		runFlags = make([]byte, (n+7)/8)
+		if _, err := io.ReadFull(r, runFlags); err != nil {
+			return nil, nil, err
+		}
+	case cookie == serialCookieNoRunContainer:
+		var size uint32
+		if err := read(&size); err != nil {
+			return nil, nil, err
+		}
+		if size > 1<<16 {
+			return nil, nil, fmt.Errorf("%w: %d containers", InvalidFormat, size)
+		}
+		n = int(size)
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown cookie %d", InvalidFormat, cookie)
+	}
+	isRun := func(i int) bool {
+		return runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0
+	}
+
+	header := make([]uint16, 2*n)
+	if err := read(header); err != nil {
+		return nil, nil, err
+	}
+	if runFlags == nil || n >= noOffsetThreshold {
+		// The containers follow one another, so there is
+		// no need for their offsets.
+		offsets := make([]uint32, n)
+		if err := read(offsets); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	keys := make([]uint16, n)
+	containers := make([]container, n)
+	for i := 0; i < n; i++ {
+		keys[i] = header[2*i]
+		if i > 0 && keys[i] <= keys[i-1] {
+			return nil, nil, fmt.Errorf("%w: keys out of order", InvalidFormat)
+		}
+		card := int(header[2*i+1]) + 1
+		switch {
+		case isRun(i):
+			var numRuns uint16
+			if err := read(&numRuns); err != nil {
+				return nil, nil, err
+			}
+			pairs := make([]uint16, 2*int(numRuns))
+			if err := read(pairs); err != nil {
+				return nil, nil, err
+			}
+			c := &runContainer{runs: make([]interval, numRuns)}
+			for j := range c.runs {
+				start, length := pairs[2*j], pairs[2*j+1]
+				if int(start)+int(length) > 0xFFFF {
+					return nil, nil, fmt.Errorf("%w: run past end of container", InvalidFormat)
+				}
+				c.runs[j] = interval{start: start, last: start + length}
+			}
+			containers[i] = c
+		case card > arrayMaxSize:
+			c := &bitmapContainer{}
+			if err := read(c.words[:]); err != nil {
+				return nil, nil, err
+			}
+			c.card = card
+			containers[i] = c
+		default:
+			c := &arrayContainer{values: make([]uint16, card)}
+			if err := read(c.values); err != nil {
+				return nil, nil, err
+			}
+			containers[i] = c
+		}
+	}
+	return keys, containers, nil
+}
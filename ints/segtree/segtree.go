@@ -0,0 +1,242 @@
+// Package segtree implements a segment tree of ints with lazy
+// range updates.
+//
+// It is an express design decision to hard-code
+// this tree just for the int type rather than for
+// the empty interface. See v3/segtree for a version that
+// works on any type.
+//
+// A segment tree holds a fixed-length array of items along with
+// the aggregate of every one of a set of O(n) ranges of them, such
+// that the aggregate of any range of items can be found, and an
+// update can be applied to every item in a range, in O(log n) time.
+// The aggregate is whatever an associative Combine function makes
+// it: a sum, a minimum, a maximum, and so on.
+//
+// Updates to a range are applied lazily: the aggregates of the
+// ranges that cover the updated range are updated at once, and the
+// update is only pushed down to smaller ranges when a later query
+// or update needs them.
+package segtree
+
+import (
+	"fmt"
+)
+
+// Ops tells the tree how to aggregate items and how to apply
+// updates to them.
+type Ops struct {
+	// Combine returns the aggregate of two adjacent runs of items,
+	// given the aggregates of each. It must be associative.
+	Combine func(a, b int) int
+	// Apply returns the aggregate of a run of n items after update
+	// u has been applied to each of them, given their aggregate x.
+	Apply func(u int, x int, n int) int
+	// Compose returns the single update that has the same effect
+	// as applying older and then newer.
+	Compose func(newer, older int) int
+}
+
+// Tree holds the items and aggregates of the segment tree. Node 1
+// covers every item, and the children of node i are 2i and 2i+1,
+// which cover the left and right halves of the items node i covers.
+// pending[i] tells whether lazy[i] holds an update that has been
+// applied to node i but not yet to its children.
+type Tree struct {
+	ops     Ops
+	n       int
+	agg     []int
+	lazy    []int
+	pending []bool
+}
+
+// New returns a new tree holding the items of data, aggregated using
+// ops. Combine must be set. Apply and Compose need only be set if
+// Update is going to be used, but one may not be set without the
+// other; New panics if they are not set that way.
+func New(data []int, ops Ops) *Tree {
+	if ops.Combine == nil {
+		panic("segtree: Combine must be set")
+	}
+	if (ops.Apply == nil) != (ops.Compose == nil) {
+		panic("segtree: Apply and Compose must both be set")
+	}
+	n := len(data)
+	t := &Tree{
+		ops: ops,
+		n:   n,
+		agg: make([]int, 4*n),
+	}
+	if ops.Apply != nil {
+		t.lazy = make([]int, 4*n)
+		t.pending = make([]bool, 4*n)
+	}
+	if n > 0 {
+		t.build(1, 0, n, data)
+	}
+	return t
+}
+
+func (t *Tree) build(node int, lo int, hi int, data []int) {
+	if hi-lo == 1 {
+		t.agg[node] = data[lo]
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.build(2*node, lo, mid, data)
+	t.build(2*node+1, mid, hi, data)
+	t.agg[node] = t.ops.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+// Len returns the number of items in the tree.
+func (t *Tree) Len() int {
+	return t.n
+}
+
+func (t *Tree) checkRange(lo int, hi int) {
+	if lo < 0 || lo > hi || hi > t.n {
+		panic(fmt.Sprintf("segtree: range [%d, %d) out of range [0, %d)", lo, hi, t.n))
+	}
+}
+
+// applyNode applies u to every item covered by node, which covers n items.
+func (t *Tree) applyNode(node int, u int, n int) {
+	t.agg[node] = t.ops.Apply(u, t.agg[node], n)
+	if n == 1 {
+		return
+	}
+	if t.pending[node] {
+		t.lazy[node] = t.ops.Compose(u, t.lazy[node])
+	} else {
+		t.lazy[node] = u
+		t.pending[node] = true
+	}
+}
+
+// push passes the pending update of node, which covers [lo, hi),
+// down to its children.
+func (t *Tree) push(node int, lo int, mid int, hi int) {
+	if t.pending == nil || !t.pending[node] {
+		return
+	}
+	t.applyNode(2*node, t.lazy[node], mid-lo)
+	t.applyNode(2*node+1, t.lazy[node], hi-mid)
+	t.lazy[node] = 0
+	t.pending[node] = false
+}
+
+// Query returns the aggregate of the items in the half-open range
+// [lo, hi). It returns false if the range is empty. It panics unless
+// 0 <= lo <= hi <= Len().
+func (t *Tree) Query(lo int, hi int) (int, bool) {
+	t.checkRange(lo, hi)
+	if lo == hi {
+		return 0, false
+	}
+	return t.query(1, 0, t.n, lo, hi), true
+}
+
+// query returns the aggregate of [qlo, qhi), which must overlap
+// [lo, hi), the range covered by node.
+func (t *Tree) query(node int, lo int, hi int, qlo int, qhi int) int {
+	if qlo <= lo && hi <= qhi {
+		return t.agg[node]
+	}
+	mid := lo + (hi-lo)/2
+	t.push(node, lo, mid, hi)
+	switch {
+	case qhi <= mid:
+		return t.query(2*node, lo, mid, qlo, qhi)
+	case qlo >= mid:
+		return t.query(2*node+1, mid, hi, qlo, qhi)
+	}
+	return t.ops.Combine(
+		t.query(2*node, lo, mid, qlo, qhi),
+		t.query(2*node+1, mid, hi, qlo, qhi))
+}
+
+// Update applies update u to every item in the half-open range
+// [lo, hi). It panics unless 0 <= lo <= hi <= Len(), or if the
+// tree was made without Apply and Compose.
+func (t *Tree) Update(lo int, hi int, u int) {
+	if t.ops.Apply == nil {
+		panic("segtree: Update needs Apply and Compose")
+	}
+	t.checkRange(lo, hi)
+	if lo < hi {
+		t.update(1, 0, t.n, lo, hi, u)
+	}
+}
+
+func (t *Tree) update(node int, lo int, hi int, qlo int, qhi int, u int) {
+	if qhi <= lo || hi <= qlo {
+		return
+	}
+	if qlo <= lo && hi <= qhi {
+		t.applyNode(node, u, hi-lo)
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.push(node, lo, mid, hi)
+	t.update(2*node, lo, mid, qlo, qhi, u)
+	t.update(2*node+1, mid, hi, qlo, qhi, u)
+	t.agg[node] = t.ops.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+// Get returns item i. It panics if i is out of range.
+func (t *Tree) Get(i int) int {
+	t.checkIndex(i)
+	x, _ := t.Query(i, i+1)
+	return x
+}
+
+// Set replaces item i with x. It panics if i is out of range.
+func (t *Tree) Set(i int, x int) {
+	t.checkIndex(i)
+	t.set(1, 0, t.n, i, x)
+}
+
+func (t *Tree) checkIndex(i int) {
+	if i < 0 || i >= t.n {
+		panic(fmt.Sprintf("segtree: index %d out of range [0, %d)", i, t.n))
+	}
+}
+
+func (t *Tree) set(node int, lo int, hi int, i int, x int) {
+	if hi-lo == 1 {
+		t.agg[node] = x
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.push(node, lo, mid, hi)
+	if i < mid {
+		t.set(2*node, lo, mid, i, x)
+	} else {
+		t.set(2*node+1, mid, hi, i, x)
+	}
+	t.agg[node] = t.ops.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+// SumAdd aggregates items by summing them, and updates
+// them by adding to them.
+var SumAdd = Ops{
+	Combine: func(a, b int) int { return a + b },
+	Apply:   func(u int, x int, n int) int { return x + u*n },
+	Compose: func(newer, older int) int { return newer + older },
+}
+
+// MinAdd aggregates items by taking their minimum, and updates
+// them by adding to them.
+var MinAdd = Ops{
+	Combine: func(a, b int) int { return min(a, b) },
+	Apply:   func(u int, x int, n int) int { return x + u },
+	Compose: func(newer, older int) int { return newer + older },
+}
+
+// MaxAdd aggregates items by taking their maximum, and updates
+// them by adding to them.
+var MaxAdd = Ops{
+	Combine: func(a, b int) int { return max(a, b) },
+	Apply:   func(u int, x int, n int) int { return x + u },
+	Compose: func(newer, older int) int { return newer + older },
+}
@@ -0,0 +1,149 @@
+package segtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEmpty(t *testing.T) {
+	tree := New(nil, SumAdd)
+	if _, ok := tree.Query(0, 0); ok {
+		t.Error("Expected an empty range to have no aggregate")
+	}
+	if tree.Len() != 0 {
+		t.Errorf("Expected length 0, got %v", tree.Len())
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tree := New([]int{5, 3, 8, 1, 9, 2}, MinAdd)
+	var tests = []struct {
+		lo   int
+		hi   int
+		want int
+	}{
+		{0, 6, 1},
+		{0, 3, 3},
+		{4, 6, 2},
+		{4, 5, 9},
+	}
+	for _, test := range tests {
+		if got, ok := tree.Query(test.lo, test.hi); !ok || got != test.want {
+			t.Errorf("Expected min of [%v, %v) to be %v, got %v", test.lo, test.hi, test.want, got)
+		}
+	}
+	tree.Update(0, 3, 10)
+	tree.Set(3, 20)
+	if got, _ := tree.Query(0, 4); got != 13 {
+		t.Errorf("Expected 13, got %v", got)
+	}
+	if got := tree.Get(1); got != 13 {
+		t.Errorf("Expected 13, got %v", got)
+	}
+}
+
+// assign updates items by replacing them, which, unlike adding,
+// does not commute, so it checks that Compose is called correctly.
+func assign() Ops {
+	return Ops{
+		Combine: func(a, b int) int { return a + b },
+		Apply:   func(u int, x int, n int) int { return u * n },
+		Compose: func(newer, older int) int { return newer },
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var tests = []struct {
+		name   string
+		ops    Ops
+		update func(u, x int) int
+		reduce func(xs []int) int
+	}{
+		{"SumAdd", SumAdd, func(u, x int) int { return x + u }, sum},
+		{"MinAdd", MinAdd, func(u, x int) int { return x + u }, minimum},
+		{"MaxAdd", MaxAdd, func(u, x int) int { return x + u }, maximum},
+		{"SumAssign", assign(), func(u, x int) int { return u }, sum},
+	}
+	for _, test := range tests {
+		for _, n := range []int{1, 2, 7, 64, 100} {
+			data := make([]int, n)
+			for i := range data {
+				data[i] = r.Intn(100) - 50
+			}
+			tree := New(data, test.ops)
+			for i := 0; i < 500; i++ {
+				lo := r.Intn(n)
+				hi := lo + 1 + r.Intn(n-lo)
+				switch r.Intn(3) {
+				case 0:
+					u := r.Intn(20) - 10
+					tree.Update(lo, hi, u)
+					for j := lo; j < hi; j++ {
+						data[j] = test.update(u, data[j])
+					}
+				case 1:
+					x := r.Intn(100) - 50
+					tree.Set(lo, x)
+					data[lo] = x
+				case 2:
+					want := test.reduce(data[lo:hi])
+					if got, _ := tree.Query(lo, hi); got != want {
+						t.Fatalf("%v n=%v: expected [%v, %v) to be %v, got %v", test.name, n, lo, hi, want, got)
+					}
+				}
+			}
+			for i, want := range data {
+				if got := tree.Get(i); got != want {
+					t.Errorf("%v n=%v: expected item %v to be %v, got %v", test.name, n, i, want, got)
+				}
+			}
+		}
+	}
+}
+
+func sum(xs []int) int {
+	s := 0
+	for _, x := range xs {
+		s += x
+	}
+	return s
+}
+
+func minimum(xs []int) int {
+	m := xs[0]
+	for _, x := range xs {
+		m = min(m, x)
+	}
+	return m
+}
+
+func maximum(xs []int) int {
+	m := xs[0]
+	for _, x := range xs {
+		m = max(m, x)
+	}
+	return m
+}
+
+func TestPanics(t *testing.T) {
+	tree := New([]int{1, 2, 3}, Ops{
+		Combine: func(a, b int) int { return a + b },
+	})
+	for name, f := range map[string]func(){
+		"Update": func() { tree.Update(0, 1, 1) },
+		"Query":  func() { tree.Query(1, 4) },
+		"Get":    func() { tree.Get(3) },
+		"Set":    func() { tree.Set(-1, 0) },
+		"New":    func() { New([]int{1}, Ops{}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected %v to panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,113 @@
+// Package sparseset implements a set for ints drawn from a fixed
+// universe [0, n), as described by Briggs and Torczon in "An
+// Efficient Representation for Sparse Sets".
+//
+// It is an express design decision to hard-code
+// this set just for the int type rather than for
+// the empty interface.
+//
+// It has the same Has/Put/Delete/PutSlice methods as ints/set.Set,
+// all of them O(1), but adds Clear, which is also O(1), no matter
+// how many members the set has. That makes it a good fit for
+// something like the visited set of a graph search, which gets
+// emptied again and again.
+//
+// The members are kept packed together in a dense slice, and
+// a sparse slice maps every int in the universe to where it
+// would be in the dense slice. An int is a member only if the
+// two agree, so emptying the set is just a matter of forgetting
+// how long the dense slice is.
+package sparseset
+
+import "fmt"
+
+// Set holds the data and state of the sparse set.
+type Set struct {
+	// dense[:size] holds the members, in no particular order.
+	dense []int
+	// sparse[k] is the index of k in dense, if k is a member.
+	sparse []int
+	size   int
+}
+
+// New returns a new empty sparse set that can hold the ints
+// 0 through universe-1. It panics if universe is negative.
+func New(universe int) *Set {
+	if universe < 0 {
+		panic(fmt.Sprintf("sparseset: universe %d is negative", universe))
+	}
+	return &Set{
+		dense:  make([]int, universe),
+		sparse: make([]int, universe),
+	}
+}
+
+func (s *Set) Has(k int) bool {
+	if k < 0 || k >= len(s.sparse) {
+		return false
+	}
+	i := s.sparse[k]
+	return i < s.size && s.dense[i] == k
+}
+
+// Put puts k in the set. It panics if k is outside the universe.
+func (s *Set) Put(k int) {
+	if k < 0 || k >= len(s.sparse) {
+		panic(fmt.Sprintf("sparseset: %d is outside the universe [0, %d)", k, len(s.sparse)))
+	}
+	if s.Has(k) {
+		return
+	}
+	s.dense[s.size] = k
+	s.sparse[k] = s.size
+	s.size++
+}
+
+func (s *Set) Delete(k int) {
+	if !s.Has(k) {
+		return
+	}
+	// Move the last member into k's slot in dense.
+	i := s.sparse[k]
+	last := s.dense[s.size-1]
+	s.dense[i] = last
+	s.sparse[last] = i
+	s.size--
+}
+
+func (s *Set) PutSlice(ks []int) {
+	for _, k := range ks {
+		s.Put(k)
+	}
+}
+
+// Clear deletes every member of the set in O(1) time.
+func (s *Set) Clear() {
+	s.size = 0
+}
+
+// Len returns the number of members of the set.
+func (s *Set) Len() int {
+	return s.size
+}
+
+// Universe returns the size of the universe the set was created with.
+func (s *Set) Universe() int {
+	return len(s.sparse)
+}
+
+// Iter iterates through every member of the set, in no particular
+// order, and calls function f using the member as an argument.
+// f must not change the set.
+func (s *Set) Iter(f func(k int)) {
+	for _, k := range s.dense[:s.size] {
+		f(k)
+	}
+}
+
+// Members returns the members of the set, in no particular order.
+// The returned slice shares memory with the set, so it is only
+// valid until the set is next changed, and must not be modified.
+func (s *Set) Members() []int {
+	return s.dense[:s.size:s.size]
+}
@@ -0,0 +1,77 @@
+package sparseset
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test(t *testing.T) {
+	s := New(10)
+	s.PutSlice([]int{1, 2, 3})
+	for i := 4; i <= 6; i++ {
+		s.Put(i)
+	}
+	for i := 1; i <= 6; i++ {
+		if !s.Has(i) {
+			t.Errorf("Expected %v to be in the set", i)
+		}
+	}
+	for i := 4; i <= 6; i++ {
+		s.Delete(i)
+	}
+	for i := 4; i <= 6; i++ {
+		if s.Has(i) {
+			t.Errorf("Did not expect %v to be in the set", i)
+		}
+	}
+	if s.Has(-1) || s.Has(10) {
+		t.Error("Did not expect ints outside the universe to be in the set")
+	}
+	s.Delete(-1)
+	s.Delete(10)
+	if s.Len() != 3 {
+		t.Errorf("Expected length 3, got %v", s.Len())
+	}
+}
+
+func TestPutOutsideUniverse(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Put to panic outside the universe")
+		}
+	}()
+	New(10).Put(10)
+}
+
+func TestClearAndIter(t *testing.T) {
+	s := New(100)
+	s.PutSlice([]int{42, 7, 99, 0, 7})
+	s.Delete(7)
+	got := []int{}
+	s.Iter(func(k int) {
+		got = append(got, k)
+	})
+	slices.Sort(got)
+	if want := []int{0, 42, 99}; !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	members := slices.Clone(s.Members())
+	slices.Sort(members)
+	if !slices.Equal(got, members) {
+		t.Errorf("Expected members %v, got %v", got, members)
+	}
+
+	s.Clear()
+	if s.Len() != 0 {
+		t.Errorf("Expected length 0 after Clear, got %v", s.Len())
+	}
+	for _, k := range got {
+		if s.Has(k) {
+			t.Errorf("Did not expect %v to be in the set after Clear", k)
+		}
+	}
+	s.Put(99)
+	if !s.Has(99) || s.Has(42) || s.Len() != 1 {
+		t.Error("Expected only 99 in the set after Clear and Put")
+	}
+}
@@ -0,0 +1,347 @@
+// Package veb implements a van Emde Boas tree: a set of ints from
+// a bounded universe [0, n) that can find the next or previous
+// member of any int in O(log log n) time.
+//
+// It is an express design decision to hard-code
+// this tree just for the int type rather than for
+// the empty interface.
+//
+// Where ints/set can only tell you whether an int is a member,
+// a van Emde Boas tree can also answer "what is the smallest
+// member greater than x?", which is what a port or slot allocator
+// needs, and it answers faster than a balanced tree's O(log n).
+//
+// A tree over a universe of 2^k ints splits each int into its
+// high k/2 bits, which pick a cluster, and its low k/2 bits,
+// which are looked up recursively in that cluster. A summary
+// tree over the cluster numbers records which clusters are not
+// empty. The smallest member of each tree is kept apart from its
+// clusters, which is what makes each operation recurse only once.
+// Universes of 64 or fewer ints are stored as a single word, and
+// clusters are only allocated once they have members.
+package veb
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// leafBits is the size, as a power of two, of a universe small
+// enough to fit in one word.
+const leafBits = 6
+
+// node is a van Emde Boas tree over a universe of 2^k ints.
+type node struct {
+	// Leaves hold their members as bits of a word.
+	leaf bool
+	bits uint64
+
+	// Internal nodes split members into clusters. min is not
+	// stored in any cluster. min and max are -1 when empty.
+	lowBits  uint
+	min      int
+	max      int
+	summary  *node
+	clusters []*node
+}
+
+func newNode(k uint) *node {
+	if k <= leafBits {
+		return &node{leaf: true}
+	}
+	lowBits := k / 2
+	return &node{
+		lowBits:  lowBits,
+		min:      -1,
+		max:      -1,
+		clusters: make([]*node, 1<<(k-lowBits)),
+	}
+}
+
+func (n *node) high(x int) int {
+	return x >> n.lowBits
+}
+
+func (n *node) low(x int) int {
+	return x & (1<<n.lowBits - 1)
+}
+
+func (n *node) index(high, low int) int {
+	return high<<n.lowBits | low
+}
+
+func (n *node) empty() bool {
+	if n == nil {
+		return true
+	}
+	if n.leaf {
+		return n.bits == 0
+	}
+	return n.min == -1
+}
+
+// minimum returns the smallest member, or -1 if there are none.
+func (n *node) minimum() int {
+	if n.leaf {
+		if n.bits == 0 {
+			return -1
+		}
+		return bits.TrailingZeros64(n.bits)
+	}
+	return n.min
+}
+
+// maximum returns the largest member, or -1 if there are none.
+func (n *node) maximum() int {
+	if n.leaf {
+		if n.bits == 0 {
+			return -1
+		}
+		return 63 - bits.LeadingZeros64(n.bits)
+	}
+	return n.max
+}
+
+func (n *node) has(x int) bool {
+	if n.leaf {
+		return n.bits&(1<<uint(x)) != 0
+	}
+	if x == n.min || x == n.max {
+		return true
+	}
+	if n.min == -1 {
+		return false
+	}
+	c := n.clusters[n.high(x)]
+	return c != nil && c.has(n.low(x))
+}
+
+// insert inserts x, which must not already be a member.
+func (n *node) insert(x int) {
+	if n.leaf {
+		n.bits |= 1 << uint(x)
+		return
+	}
+	if n.min == -1 {
+		n.min = x
+		n.max = x
+		return
+	}
+	if x < n.min {
+		// x becomes the new min, and the old min goes
+		// into a cluster in its place.
+		x, n.min = n.min, x
+	}
+	h := n.high(x)
+	c := n.clusters[h]
+	if c == nil {
+		c = newNode(n.lowBits)
+		n.clusters[h] = c
+	}
+	if c.empty() {
+		if n.summary == nil {
+			n.summary = newNode(uint(bits.Len(uint(len(n.clusters) - 1))))
+		}
+		n.summary.insert(h)
+	}
+	c.insert(n.low(x))
+	if x > n.max {
+		n.max = x
+	}
+}
+
+// delete deletes x, which must be a member.
+func (n *node) delete(x int) {
+	if n.leaf {
+		n.bits &^= 1 << uint(x)
+		return
+	}
+	if n.min == n.max {
+		n.min = -1
+		n.max = -1
+		return
+	}
+	if x == n.min {
+		// Pull the smallest member out of the clusters
+		// to be the new min, and delete it from there instead.
+		first := n.summary.minimum()
+		x = n.index(first, n.clusters[first].minimum())
+		n.min = x
+	}
+	h := n.high(x)
+	c := n.clusters[h]
+	c.delete(n.low(x))
+	if c.empty() {
+		n.clusters[h] = nil
+		n.summary.delete(h)
+	}
+	if x == n.max {
+		if n.summary.empty() {
+			n.max = n.min
+		} else {
+			last := n.summary.maximum()
+			n.max = n.index(last, n.clusters[last].maximum())
+		}
+	}
+}
+
+// successor returns the smallest member greater than x, or -1.
+func (n *node) successor(x int) int {
+	if n.leaf {
+		if x >= 63 {
+			return -1
+		}
+		above := n.bits & (^uint64(0) << uint(x+1))
+		if above == 0 {
+			return -1
+		}
+		return bits.TrailingZeros64(above)
+	}
+	if n.min != -1 && x < n.min {
+		return n.min
+	}
+	h, l := n.high(x), n.low(x)
+	if c := n.clusters[h]; !c.empty() && l < c.maximum() {
+		return n.index(h, c.successor(l))
+	}
+	if n.summary.empty() {
+		return -1
+	}
+	next := n.summary.successor(h)
+	if next == -1 {
+		return -1
+	}
+	return n.index(next, n.clusters[next].minimum())
+}
+
+// predecessor returns the largest member less than x, or -1.
+func (n *node) predecessor(x int) int {
+	if n.leaf {
+		if x <= 0 {
+			return -1
+		}
+		below := n.bits & (1<<uint(x) - 1)
+		if below == 0 {
+			return -1
+		}
+		return 63 - bits.LeadingZeros64(below)
+	}
+	if n.max != -1 && x > n.max {
+		return n.max
+	}
+	h, l := n.high(x), n.low(x)
+	if c := n.clusters[h]; !c.empty() && l > c.minimum() {
+		return n.index(h, c.predecessor(l))
+	}
+	if !n.summary.empty() {
+		if prev := n.summary.predecessor(h); prev != -1 {
+			return n.index(prev, n.clusters[prev].maximum())
+		}
+	}
+	// min is not in any cluster, so check it last.
+	if n.min != -1 && x > n.min {
+		return n.min
+	}
+	return -1
+}
+
+// Tree holds a van Emde Boas tree and its universe.
+type Tree struct {
+	root     *node
+	universe int
+	size     int
+}
+
+// New returns a new empty tree that can hold the ints 0 through
+// universe-1. It panics if universe is negative. Memory use grows
+// with the square root of the universe even when the tree is empty,
+// so the universe should be no larger than it needs to be.
+func New(universe int) *Tree {
+	if universe < 0 {
+		panic(fmt.Sprintf("veb: universe %d is negative", universe))
+	}
+	k := uint(0)
+	if universe > 1 {
+		k = uint(bits.Len(uint(universe - 1)))
+	}
+	return &Tree{
+		root:     newNode(k),
+		universe: universe,
+	}
+}
+
+func (t *Tree) inUniverse(x int) bool {
+	return x >= 0 && x < t.universe
+}
+
+// Has tells you whether x is in the tree.
+func (t *Tree) Has(x int) bool {
+	return t.inUniverse(x) && t.root.has(x)
+}
+
+// Insert puts x in the tree. It panics if x is outside the universe.
+func (t *Tree) Insert(x int) {
+	if !t.inUniverse(x) {
+		panic(fmt.Sprintf("veb: %d is outside the universe [0, %d)", x, t.universe))
+	}
+	if t.root.has(x) {
+		return
+	}
+	t.root.insert(x)
+	t.size++
+}
+
+// Delete deletes x from the tree.
+func (t *Tree) Delete(x int) {
+	if !t.Has(x) {
+		return
+	}
+	t.root.delete(x)
+	t.size--
+}
+
+// Len returns the number of members of the tree.
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Min returns the smallest member of the tree. It returns false if
+// the tree is empty.
+func (t *Tree) Min() (int, bool) {
+	x := t.root.minimum()
+	return x, x != -1
+}
+
+// Max returns the largest member of the tree. It returns false if
+// the tree is empty.
+func (t *Tree) Max() (int, bool) {
+	x := t.root.maximum()
+	return x, x != -1
+}
+
+// Successor returns the smallest member of the tree greater than x.
+// It returns false if there is no such member. For the smallest
+// member greater than or equal to x, check Has(x) first.
+func (t *Tree) Successor(x int) (int, bool) {
+	if x < 0 {
+		return t.Min()
+	}
+	if x >= t.universe-1 {
+		return 0, false
+	}
+	y := t.root.successor(x)
+	return y, y != -1
+}
+
+// Predecessor returns the largest member of the tree less than x.
+// It returns false if there is no such member.
+func (t *Tree) Predecessor(x int) (int, bool) {
+	if x >= t.universe {
+		return t.Max()
+	}
+	if x <= 0 {
+		return 0, false
+	}
+	y := t.root.predecessor(x)
+	return y, y != -1
+}
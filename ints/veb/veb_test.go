@@ -0,0 +1,115 @@
+package veb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/ints/set"
+)
+
+func TestSmall(t *testing.T) {
+	tr := New(16)
+	if _, ok := tr.Min(); ok {
+		t.Error("Expected no min in an empty tree")
+	}
+	for _, x := range []int{3, 9, 1, 15, 9} {
+		tr.Insert(x)
+	}
+	if tr.Len() != 4 {
+		t.Errorf("Expected length 4, got %v", tr.Len())
+	}
+	if x, _ := tr.Min(); x != 1 {
+		t.Errorf("Expected min 1, got %v", x)
+	}
+	if x, _ := tr.Max(); x != 15 {
+		t.Errorf("Expected max 15, got %v", x)
+	}
+	if x, _ := tr.Successor(3); x != 9 {
+		t.Errorf("Expected successor of 3 to be 9, got %v", x)
+	}
+	if x, _ := tr.Predecessor(9); x != 3 {
+		t.Errorf("Expected predecessor of 9 to be 3, got %v", x)
+	}
+	if _, ok := tr.Successor(15); ok {
+		t.Error("Expected no successor of 15")
+	}
+	tr.Delete(1)
+	tr.Delete(2)
+	if tr.Has(1) || !tr.Has(3) {
+		t.Error("Has was not as expected after Delete")
+	}
+	if x, _ := tr.Min(); x != 3 {
+		t.Errorf("Expected min 3, got %v", x)
+	}
+}
+
+func TestInsertOutsideUniverse(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Insert to panic outside the universe")
+		}
+	}()
+	New(100).Insert(100)
+}
+
+// TestRandom checks a tree against ints/set.Set for universes that
+// are small enough to be leaves, one level deep, and several levels
+// deep, and that are not powers of two.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, universe := range []int{1, 50, 64, 1000, 1 << 16, 3000000} {
+		tr := New(universe)
+		s := set.New()
+		for i := 0; i < 3000; i++ {
+			x := r.Intn(universe)
+			if r.Intn(3) == 0 {
+				tr.Delete(x)
+				s.Delete(x)
+			} else {
+				tr.Insert(x)
+				s.Put(x)
+			}
+			if tr.Len() != len(s) {
+				t.Fatalf("universe %v: expected length %v, got %v", universe, len(s), tr.Len())
+			}
+			// Check the neighbourhood of a random int.
+			q := r.Intn(universe)
+			if tr.Has(q) != s.Has(q) {
+				t.Fatalf("universe %v: expected Has(%v) to be %v", universe, q, s.Has(q))
+			}
+			checkNeighbours(t, universe, tr, s, q)
+		}
+		// And then every int in a small universe.
+		if universe <= 1000 {
+			for q := -1; q <= universe; q++ {
+				checkNeighbours(t, universe, tr, s, q)
+			}
+		}
+	}
+}
+
+func checkNeighbours(t *testing.T, universe int, tr *Tree, s set.Set, q int) {
+	wantSucc, wantPred := -1, -1
+	for x := range s {
+		if x > q && (wantSucc == -1 || x < wantSucc) {
+			wantSucc = x
+		}
+		if x < q && x > wantPred {
+			wantPred = x
+		}
+	}
+	succ, ok := tr.Successor(q)
+	if !ok {
+		succ = -1
+	}
+	if succ != wantSucc {
+		t.Fatalf("universe %v: expected Successor(%v) to be %v, got %v", universe, q, wantSucc, succ)
+	}
+	pred, ok := tr.Predecessor(q)
+	if !ok {
+		pred = -1
+	}
+	if pred != wantPred {
+		t.Fatalf("universe %v: expected Predecessor(%v) to be %v, got %v", universe, q, wantPred, pred)
+	}
+}
@@ -0,0 +1,375 @@
+// Package ahocorasick implements the Aho-Corasick algorithm,
+// which finds every occurrence of any of a set of patterns in
+// a text in a single pass over the text.
+//
+// It is an express design decision to hard-code
+// this matcher just for the string type rather than for
+// the empty interface.
+//
+// The matcher is built once from a strings/set.Set of patterns
+// into a trie of the patterns, in which each node also has a
+// failure link to the node for the longest proper suffix of its
+// string that is also in the trie. Scanning a text then follows
+// trie edges where it can and failure links where it cannot, so it
+// takes time proportional to the length of the text plus the number
+// of matches, no matter how many patterns there are.
+//
+// Patterns are matched rune by rune, so that case folding works
+// for all of Unicode and not just ASCII. A byte that is not part of
+// valid UTF-8, in a pattern or in the text, only matches the same
+// byte. Offsets are byte offsets into the text, as for the strings
+// package.
+package ahocorasick
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+// Options change how the matcher matches.
+type Options struct {
+	// CaseFold makes the matcher ignore case, using Unicode
+	// simple case folding, the same as strings.EqualFold.
+	CaseFold bool
+	// LeftmostLongest makes the matcher report only
+	// non-overlapping matches: of the matches that start
+	// earliest in the text, the longest, and then the same
+	// again for the rest of the text after that match,
+	// the way a regular expression alternation with
+	// leftmost-longest semantics would.
+	LeftmostLongest bool
+}
+
+// Match is an occurrence of a pattern in a text.
+type Match struct {
+	// Pattern is the pattern that matched, as it was given to
+	// New, even if it matched text of a different case.
+	Pattern string
+	// Start and End are the byte offsets of the match in the text,
+	// so that the text that matched is text[Start:End].
+	Start int
+	End   int
+}
+
+// Matcher holds the states of the automaton built from the
+// patterns. State 0 is the root of the trie.
+type Matcher struct {
+	opts     Options
+	patterns []string
+	states   []state
+	// maxDepth is the length in labels of the longest pattern.
+	maxDepth int
+}
+
+type state struct {
+	// next maps the label of each edge out of this state to the
+	// state it leads to. A label is a rune, or for a byte that is
+	// not part of valid UTF-8, -1 minus the byte.
+	next map[rune]int
+	// fail is the state for the longest proper suffix of this
+	// state's string that is also in the trie.
+	fail int
+	// depth is the length of this state's string in labels.
+	depth int
+	// out holds the patterns that end at this state.
+	out []int
+	// dict is the nearest state along the failure links that has
+	// patterns ending at it, or -1 if there is none.
+	dict int
+}
+
+// New returns a new matcher for the members of patterns. The empty
+// string is never matched, even if it is a member of patterns.
+func New(patterns set.Set, opts Options) *Matcher {
+	m := &Matcher{
+		opts:   opts,
+		states: []state{{next: make(map[rune]int), dict: -1}},
+	}
+	for p := range patterns {
+		if p != "" {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+	// Sort the patterns so that matches come out in the same
+	// order every time, since a set has no order.
+	sort.Strings(m.patterns)
+	for i, p := range m.patterns {
+		s := 0
+		for j := 0; j < len(p); {
+			r, size := m.label(p[j:])
+			j += size
+			next, ok := m.states[s].next[r]
+			if !ok {
+				next = len(m.states)
+				m.states = append(m.states, state{
+					next:  make(map[rune]int),
+					depth: m.states[s].depth + 1,
+					dict:  -1,
+				})
+				m.states[s].next[r] = next
+			}
+			s = next
+		}
+		m.states[s].out = append(m.states[s].out, i)
+		m.maxDepth = max(m.maxDepth, m.states[s].depth)
+	}
+	m.link()
+	return m
+}
+
+// link sets the failure and dictionary links of every state,
+// visiting the states in breadth-first order, so that the links
+// of every shallower state are already set.
+func (m *Matcher) link() {
+	queue := []int{0}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		// Visit the children in a fixed order, for the same
+		// reason the patterns are sorted.
+		runes := make([]rune, 0, len(m.states[s].next))
+		for r := range m.states[s].next {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+		for _, r := range runes {
+			child := m.states[s].next[r]
+			if s != 0 {
+				m.states[child].fail = m.step(m.states[s].fail, r)
+			}
+			fail := m.states[child].fail
+			if len(m.states[fail].out) > 0 {
+				m.states[child].dict = fail
+			} else {
+				m.states[child].dict = m.states[fail].dict
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// step returns the state the automaton moves to from state s
+// on reading label r.
+func (m *Matcher) step(s int, r rune) int {
+	for {
+		if next, ok := m.states[s].next[r]; ok {
+			return next
+		}
+		if s == 0 {
+			return 0
+		}
+		s = m.states[s].fail
+	}
+}
+
+// label returns the label for the first rune of s, folded, and the
+// number of bytes in the rune. Each byte that is not part of valid
+// UTF-8 gets a label of its own, which no rune shares, rather than
+// decoding to utf8.RuneError, which would match every such byte and
+// a real U+FFFD as well.
+func (m *Matcher) label(s string) (rune, int) {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError && size == 1 {
+		return -1 - rune(s[0]), 1
+	}
+	return m.fold(r), size
+}
+
+// fold returns the smallest rune that r is equivalent to under
+// simple case folding, if case folding is on, or r if it is not.
+func (m *Matcher) fold(r rune) rune {
+	if !m.opts.CaseFold {
+		return r
+	}
+	smallest := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < smallest {
+			smallest = f
+		}
+	}
+	return smallest
+}
+
+// Len returns the number of patterns in the matcher.
+func (m *Matcher) Len() int {
+	return len(m.patterns)
+}
+
+// Iter finds every match in text and calls function f using the
+// match as an argument. Unless the matcher is leftmost-longest,
+// matches are reported in order of where they end, and matches
+// that end at the same place from longest to shortest, and
+// matches may overlap.
+func (m *Matcher) Iter(text string, f func(match Match)) {
+	if m.opts.LeftmostLongest {
+		m.leftmostLongest(text, f)
+		return
+	}
+	m.scan(text, f)
+}
+
+// FindAll returns every match in text, in the order Iter reports them.
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	m.Iter(text, func(match Match) {
+		matches = append(matches, match)
+	})
+	return matches
+}
+
+// Contains tells you whether any pattern occurs in text.
+func (m *Matcher) Contains(text string) bool {
+	s := 0
+	for i := 0; i < len(text); {
+		r, size := m.label(text[i:])
+		i += size
+		s = m.step(s, r)
+		if len(m.states[s].out) > 0 || m.states[s].dict >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// scan reports every match in text, overlapping or not.
+func (m *Matcher) scan(text string, f func(match Match)) {
+	// starts holds the byte offsets of the last maxDepth labels
+	// read, so that a match's start can be found from its length
+	// in labels.
+	starts := make([]int, m.maxDepth+1)
+	s := 0
+	for i, n := 0, 0; i < len(text); n++ {
+		r, size := m.label(text[i:])
+		starts[n%len(starts)] = i
+		i += size
+		s = m.step(s, r)
+		for o := s; o >= 0; o = m.states[o].dict {
+			start := starts[(n+1-m.states[o].depth)%len(starts)]
+			for _, p := range m.states[o].out {
+				f(Match{Pattern: m.patterns[p], Start: start, End: i})
+			}
+		}
+	}
+}
+
+// leftmostLongest reports the non-overlapping matches that a
+// leftmost-longest matcher reports, as it finds them, in a single
+// pass over the text.
+//
+// The current state is kept to strings that start no earlier than
+// the last reported match ended, by following failure links, which
+// leaves it just where a fresh scan from there would be. Each end
+// read is filed under the start of the longest match ending there
+// that is not overlapped by a reported match, which is the first
+// one along the dictionary links. The earliest start with a match
+// filed under it is reported, with its longest match, as soon as
+// the current state's string starts after it, since no match that
+// starts at or before it is left to find. Reporting a match moves
+// the ends filed under the starts it covers along their dictionary
+// links, to the next match that it does not overlap, so each label
+// read costs the same however many patterns overlap, unless a
+// reported match covers the starts of matches that end after it.
+func (m *Matcher) leftmostLongest(text string, f func(match Match)) {
+	// Pending starts and ends are never more than the longest
+	// pattern behind the last label read, so rings of this size
+	// hold them all, with their byte offsets.
+	ring := m.maxDepth + 2
+	offsets := make([]int, ring)
+	type end struct {
+		// state has as its first pattern the match filed for
+		// this end.
+		state int
+		// link is the next end filed under the same start, or -1.
+		link int
+	}
+	ends := make([]end, ring)
+	type start struct {
+		// head is the last end filed under this start, or -1.
+		head int
+		// longest is the furthest end filed under this start,
+		// or -1.
+		longest int
+	}
+	starts := make([]start, ring)
+	for k := range starts {
+		starts[k] = start{head: -1, longest: -1}
+	}
+	// s is the current state, n the number of labels read, at the
+	// end of the last reported match and next the earliest start
+	// not yet reported or passed over, all counted in labels.
+	s, n, at, next := 0, 0, 0, 0
+	// live returns the start of the current state's string, once
+	// it is cut down to start no earlier than at.
+	live := func() int {
+		for m.states[s].depth > n-at {
+			s = m.states[s].fail
+		}
+		return n - m.states[s].depth
+	}
+	// file files end j under the start of the first match along
+	// the dictionary links from state o that starts no earlier
+	// than at, if there is one.
+	file := func(j, o int) {
+		for ; o >= 0 && j > at; o = m.states[o].dict {
+			st := j - m.states[o].depth
+			if st < at {
+				continue
+			}
+			ends[j%ring] = end{state: o, link: starts[st%ring].head}
+			starts[st%ring].head = j
+			starts[st%ring].longest = max(starts[st%ring].longest, j)
+			return
+		}
+	}
+	// report reports every pending match that no label still to be
+	// read can change, or all of them if final is true.
+	report := func(final bool) {
+		for {
+			limit := live()
+			if final {
+				limit = n
+			}
+			if next >= limit {
+				return
+			}
+			j := starts[next%ring].longest
+			if j < 0 {
+				next++
+				continue
+			}
+			// Patterns are added to a state in sorted order, so
+			// of the patterns that match the same text, the
+			// first in sorted order wins.
+			o := ends[j%ring].state
+			f(Match{Pattern: m.patterns[m.states[o].out[0]], Start: offsets[next%ring], End: offsets[j%ring]})
+			at = j
+			for ; next < at; next++ {
+				k := starts[next%ring].head
+				starts[next%ring] = start{head: -1, longest: -1}
+				for k >= 0 {
+					link := ends[k%ring].link
+					file(k, m.states[ends[k%ring].state].dict)
+					k = link
+				}
+			}
+		}
+	}
+	for i := 0; i < len(text); {
+		r, size := m.label(text[i:])
+		i += size
+		n++
+		offsets[n%ring] = i
+		s = m.step(s, r)
+		live()
+		o := s
+		if len(m.states[o].out) == 0 {
+			o = m.states[o].dict
+		}
+		file(n, o)
+		report(false)
+	}
+	report(true)
+}
@@ -0,0 +1,247 @@
+package ahocorasick
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+func patterns(ps ...string) set.Set {
+	s := set.New()
+	s.PutSlice(ps)
+	return s
+}
+
+func TestFindAll(t *testing.T) {
+	m := New(patterns("he", "she", "his", "hers", ""), Options{})
+	if m.Len() != 4 {
+		t.Errorf("Expected 4 patterns, got %v", m.Len())
+	}
+	want := []Match{
+		{"she", 1, 4},
+		{"he", 2, 4},
+		{"hers", 2, 6},
+	}
+	if got := m.FindAll("ushers"); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if m.Contains("usual") {
+		t.Error("Did not expect a match in usual")
+	}
+	if !m.Contains("this") {
+		t.Error("Expected a match in this")
+	}
+}
+
+func TestCaseFold(t *testing.T) {
+	m := New(patterns("straße", "KELVIN"), Options{CaseFold: true})
+	// The Kelvin sign is three bytes long, and folds to k.
+	text := "STRAßE at 5 Kelvin"
+	want := []Match{
+		{"straße", 0, 7},
+		{"KELVIN", 13, 21},
+	}
+	if got := m.FindAll(text); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := New(patterns("straße"), Options{}).FindAll(text); got != nil {
+		t.Errorf("Did not expect a match without case folding, got %v", got)
+	}
+}
+
+func TestLeftmostLongest(t *testing.T) {
+	m := New(patterns("abcd", "bc", "b", "abc", "cde", "e"), Options{LeftmostLongest: true})
+	want := []Match{
+		{"abcd", 0, 4},
+		{"e", 4, 5},
+		{"b", 6, 7},
+	}
+	if got := m.FindAll("abcdexb"); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestRandom checks every match against strings.HasPrefix, using a
+// small alphabet so that patterns overlap a lot.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomString := func(n int) string {
+		rs := make([]rune, n)
+		for i := range rs {
+			rs[i] = []rune("abé")[r.Intn(3)]
+		}
+		return string(rs)
+	}
+	for i := 0; i < 50; i++ {
+		ps := patterns()
+		for j := 0; j < 10; j++ {
+			ps.Put(randomString(1 + r.Intn(4)))
+		}
+		text := randomString(200)
+		var want []Match
+		for p := range ps {
+			for start := 0; start < len(text); start++ {
+				if strings.HasPrefix(text[start:], p) {
+					want = append(want, Match{p, start, start + len(p)})
+				}
+			}
+		}
+		got := New(ps, Options{}).FindAll(text)
+		sortMatches(want)
+		sortMatches(got)
+		if !slices.Equal(want, got) {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func sortMatches(ms []Match) {
+	sort.Slice(ms, func(i, j int) bool {
+		return compareMatches(ms[i], ms[j]) < 0
+	})
+}
+
+func compareMatches(a, b Match) int {
+	switch {
+	case a.Start != b.Start:
+		return a.Start - b.Start
+	case a.End != b.End:
+		return a.End - b.End
+	}
+	return strings.Compare(a.Pattern, b.Pattern)
+}
+
+// TestInvalidUTF8 checks that a byte that is not valid UTF-8 only
+// matches itself, and not other invalid bytes or U+FFFD.
+func TestInvalidUTF8(t *testing.T) {
+	var tests = []struct {
+		pattern string
+		text    string
+		want    []Match
+	}{
+		{"\xff", "a\xfeb\xff", []Match{{"\xff", 3, 4}}},
+		{"\xff", "�", nil},
+		{"�", "a\xffb\xfe", nil},
+		{"�", "\xff�", []Match{{"�", 1, 4}}},
+		{"a\xe2\x82", "a\xe2\x82\xac a\xe2\x82b", []Match{{"a\xe2\x82", 5, 8}}},
+	}
+	for _, test := range tests {
+		for _, opts := range []Options{{}, {CaseFold: true}, {LeftmostLongest: true}} {
+			m := New(patterns(test.pattern), opts)
+			if got := m.FindAll(test.text); !slices.Equal(test.want, got) {
+				t.Errorf("%q in %q with %+v: expected %v, got %v", test.pattern, test.text, opts, test.want, got)
+			}
+			if got := m.Contains(test.text); got != (test.want != nil) {
+				t.Errorf("%q in %q with %+v: expected Contains to be %v", test.pattern, test.text, opts, test.want != nil)
+			}
+		}
+	}
+}
+
+// TestLeftmostLongestRandom checks leftmost-longest matching against
+// choosing greedily among every match.
+func TestLeftmostLongestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomString := func(n int) string {
+		b := make([]string, n)
+		for i := range b {
+			b[i] = []string{"a", "b", "é", "\xff"}[r.Intn(4)]
+		}
+		return strings.Join(b, "")
+	}
+	for i := 0; i < 200; i++ {
+		ps := patterns()
+		for j := 0; j < 1+r.Intn(10); j++ {
+			ps.Put(randomString(1 + r.Intn(6)))
+		}
+		text := randomString(100)
+		var all []Match
+		for p := range ps {
+			for start := 0; start < len(text); start++ {
+				if strings.HasPrefix(text[start:], p) {
+					all = append(all, Match{p, start, start + len(p)})
+				}
+			}
+		}
+		sort.Slice(all, func(i, j int) bool {
+			if all[i].Start != all[j].Start {
+				return all[i].Start < all[j].Start
+			}
+			return all[i].End > all[j].End
+		})
+		var want []Match
+		free := 0
+		for _, match := range all {
+			if match.Start >= free {
+				want = append(want, match)
+				free = match.End
+			}
+		}
+		got := New(ps, Options{LeftmostLongest: true}).FindAll(text)
+		if !slices.Equal(want, got) {
+			t.Fatalf("Patterns %v in %q: expected %v, got %v", ps, text, want, got)
+		}
+	}
+}
+
+// TestLeftmostLongestOverlapping checks that leftmost-longest
+// matching does not pay for every overlapping match, of which there
+// are len(ps) at almost every point in the text.
+func TestLeftmostLongestOverlapping(t *testing.T) {
+	ps := patterns()
+	for i := 1; i <= 100; i++ {
+		ps.Put(strings.Repeat("a", i))
+	}
+	m := New(ps, Options{LeftmostLongest: true})
+	text := strings.Repeat("a", 20050)
+	count := 0
+	allocs := testing.AllocsPerRun(1, func() {
+		count = 0
+		m.Iter(text, func(match Match) {
+			count++
+		})
+	})
+	if count != 201 {
+		t.Errorf("Expected 201 matches, got %v", count)
+	}
+	if allocs > 5 {
+		t.Errorf("Expected at most 5 allocations, got %v", allocs)
+	}
+}
+
+// TestLeftmostLongestSharedPrefix checks that leftmost-longest
+// matching does not go back over the text each time a long pattern
+// that shares a prefix with a short one fails to match, by timing
+// it against reporting every match, which takes a single pass.
+func TestLeftmostLongestSharedPrefix(t *testing.T) {
+	ps := patterns()
+	ps.Put("a")
+	ps.Put(strings.Repeat("a", 2000) + "b")
+	text := strings.Repeat("a", 200000)
+	elapsed := func(m *Matcher) time.Duration {
+		fastest := time.Duration(math.MaxInt64)
+		for i := 0; i < 3; i++ {
+			count := 0
+			began := time.Now()
+			m.Iter(text, func(match Match) {
+				count++
+			})
+			fastest = min(fastest, time.Since(began))
+			if count != len(text) {
+				t.Fatalf("Expected %v matches, got %v", len(text), count)
+			}
+		}
+		return fastest
+	}
+	overlapping := elapsed(New(ps, Options{}))
+	leftmostLongest := elapsed(New(ps, Options{LeftmostLongest: true}))
+	if leftmostLongest > 20*overlapping {
+		t.Errorf("Expected leftmost-longest matching to take about as long as reporting every match (%v), got %v", overlapping, leftmostLongest)
+	}
+}
@@ -0,0 +1,240 @@
+// Package arenaset implements a set for strings that keeps the
+// bytes of its keys in a few large arenas rather than as a separate
+// heap string per key.
+//
+// It is an express design decision to hard-code
+// this set just for the string type rather than for
+// the empty interface.
+//
+// strings/set.Set holds a string header, and so a pointer, for every
+// key, all of which the garbage collector has to scan. A Set here
+// instead copies the bytes of each key onto the end of an arena, and
+// finds keys through an open-addressing index of slots that hold only
+// the hash, arena, offset and length of each key. Neither the arenas
+// nor the index contain pointers, so however many keys the set holds,
+// the garbage collector only has the handful of arenas to look at.
+//
+// Deleting a key frees its slot but not its bytes, which stay in
+// their arena until Compact is called.
+package arenaset
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+)
+
+// arenaSize is the size of each arena. A key longer than this gets
+// an arena to itself.
+const arenaSize = 1 << 20
+
+const minSlots = 8
+
+const (
+	empty uint8 = iota
+	full
+	deleted
+)
+
+// slot is an entry in the index. The bytes of its key are
+// arenas[arena][off : off+length].
+type slot struct {
+	hash   uint64
+	arena  uint32
+	off    uint32
+	length uint32
+	state  uint8
+}
+
+type Set struct {
+	seed   maphash.Seed
+	arenas [][]byte
+	slots  []slot
+	// count is the number of full slots, and tombstones the
+	// number of deleted ones, which still have to be probed past.
+	count      int
+	tombstones int
+	// wasted is the number of bytes in the arenas that belong
+	// to deleted keys.
+	wasted int
+}
+
+// New returns a new empty set.
+func New() *Set {
+	return &Set{
+		seed:  maphash.MakeSeed(),
+		slots: make([]slot, minSlots),
+	}
+}
+
+// key returns the bytes of the key in sl.
+func (s *Set) key(sl *slot) []byte {
+	return s.arenas[sl.arena][sl.off : sl.off+sl.length]
+}
+
+// find returns the index of the slot holding k, or -1 if k is not
+// in the set.
+func (s *Set) find(k string, hash uint64) int {
+	mask := len(s.slots) - 1
+	for i := int(hash) & mask; ; i = (i + 1) & mask {
+		sl := &s.slots[i]
+		switch {
+		case sl.state == empty:
+			return -1
+		case sl.state == full && sl.hash == hash && string(s.key(sl)) == k:
+			return i
+		}
+	}
+}
+
+func (s *Set) Has(k string) bool {
+	return s.find(k, maphash.String(s.seed, k)) >= 0
+}
+
+func (s *Set) Put(k string) {
+	if uint64(len(k)) > math.MaxUint32 {
+		panic(fmt.Sprintf("arenaset: key of %d bytes is too long", len(k)))
+	}
+	hash := maphash.String(s.seed, k)
+	if s.find(k, hash) >= 0 {
+		return
+	}
+	if (s.count+s.tombstones+1)*4 > len(s.slots)*3 {
+		s.rehash()
+	}
+	arena, off := s.reserve(len(k))
+	s.arenas[arena] = append(s.arenas[arena], k...)
+	s.insert(slot{
+		hash:   hash,
+		arena:  arena,
+		off:    off,
+		length: uint32(len(k)),
+		state:  full,
+	})
+	s.count++
+}
+
+// reserve returns the arena, and the offset in it, that the next
+// key of length n should be appended at, starting a new arena if
+// the last one does not have room.
+func (s *Set) reserve(n int) (arena, off uint32) {
+	last := len(s.arenas) - 1
+	if last < 0 || cap(s.arenas[last])-len(s.arenas[last]) < n {
+		s.arenas = append(s.arenas, make([]byte, 0, max(arenaSize, n)))
+		last++
+	}
+	return uint32(last), uint32(len(s.arenas[last]))
+}
+
+// insert puts sl in the first free slot along its probe sequence,
+// reusing a tombstone if there is one.
+func (s *Set) insert(sl slot) {
+	mask := len(s.slots) - 1
+	for i := int(sl.hash) & mask; ; i = (i + 1) & mask {
+		switch s.slots[i].state {
+		case deleted:
+			s.tombstones--
+			fallthrough
+		case empty:
+			s.slots[i] = sl
+			return
+		}
+	}
+}
+
+// rehash rebuilds the index without tombstones, doubling its size
+// if it is more than half full of keys.
+func (s *Set) rehash() {
+	n := len(s.slots)
+	if (s.count+1)*2 > n {
+		n *= 2
+	}
+	old := s.slots
+	s.slots = make([]slot, n)
+	s.tombstones = 0
+	for i := range old {
+		if old[i].state == full {
+			s.insert(old[i])
+		}
+	}
+}
+
+// Delete deletes k from the set. The bytes of k stay in their
+// arena until Compact is called.
+func (s *Set) Delete(k string) {
+	i := s.find(k, maphash.String(s.seed, k))
+	if i < 0 {
+		return
+	}
+	s.wasted += int(s.slots[i].length)
+	s.slots[i] = slot{state: deleted}
+	s.count--
+	s.tombstones++
+}
+
+func (s *Set) PutSlice(ks []string) {
+	for _, k := range ks {
+		s.Put(k)
+	}
+}
+
+// Len returns the number of keys in the set.
+func (s *Set) Len() int {
+	return s.count
+}
+
+// ArenaBytes returns the number of bytes of keys, deleted or not,
+// held in the arenas.
+func (s *Set) ArenaBytes() int {
+	total := 0
+	for _, a := range s.arenas {
+		total += len(a)
+	}
+	return total
+}
+
+// WastedBytes returns the number of bytes in the arenas that belong
+// to deleted keys, which Compact would reclaim.
+func (s *Set) WastedBytes() int {
+	return s.wasted
+}
+
+// Compact copies the remaining keys into new arenas, so that the
+// bytes of deleted keys can be garbage collected, and rebuilds the
+// index without tombstones, shrinking it if most of it is empty.
+func (s *Set) Compact() {
+	old := s.arenas
+	s.arenas = nil
+	live := make([]slot, 0, s.count)
+	for i := range s.slots {
+		sl := s.slots[i]
+		if sl.state != full {
+			continue
+		}
+		k := old[sl.arena][sl.off : sl.off+sl.length]
+		sl.arena, sl.off = s.reserve(len(k))
+		s.arenas[sl.arena] = append(s.arenas[sl.arena], k...)
+		live = append(live, sl)
+	}
+	n := minSlots
+	for s.count*4 > n*3/2 {
+		n *= 2
+	}
+	s.slots = make([]slot, n)
+	s.tombstones = 0
+	s.wasted = 0
+	for _, sl := range live {
+		s.insert(sl)
+	}
+}
+
+// Iter iterates through every key in the set, in no particular
+// order, and calls function f using the key as an argument.
+// The set must not be changed while Iter is running.
+func (s *Set) Iter(f func(k string)) {
+	for i := range s.slots {
+		if s.slots[i].state == full {
+			f(string(s.key(&s.slots[i])))
+		}
+	}
+}
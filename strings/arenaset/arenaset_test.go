@@ -0,0 +1,105 @@
+package arenaset
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := New()
+	s.PutSlice([]string{"apple", "banana", "", "cherry", "banana"})
+	if s.Len() != 4 {
+		t.Errorf("Expected 4 keys, got %v", s.Len())
+	}
+	var tests = []struct {
+		k    string
+		want bool
+	}{
+		{"apple", true},
+		{"banana", true},
+		{"", true},
+		{"cherry", true},
+		{"apple ", false},
+		{"banan", false},
+	}
+	for _, test := range tests {
+		if got := s.Has(test.k); got != test.want {
+			t.Errorf("Expected Has(%q) to be %v, got %v", test.k, test.want, got)
+		}
+	}
+	s.Delete("banana")
+	s.Delete("durian")
+	if s.Has("banana") || s.Len() != 3 {
+		t.Errorf("Expected banana to be deleted, leaving 3 keys, got %v", s.Len())
+	}
+	if s.ArenaBytes() != 17 || s.WastedBytes() != 6 {
+		t.Errorf("Expected 17 bytes with 6 wasted, got %v with %v wasted", s.ArenaBytes(), s.WastedBytes())
+	}
+	s.Compact()
+	if s.ArenaBytes() != 11 || s.WastedBytes() != 0 {
+		t.Errorf("Expected 11 bytes with none wasted, got %v with %v wasted", s.ArenaBytes(), s.WastedBytes())
+	}
+	got := make(map[string]bool)
+	s.Iter(func(k string) {
+		got[k] = true
+	})
+	if len(got) != 3 || !got["apple"] || !got[""] || !got["cherry"] {
+		t.Errorf("Expected apple, cherry and the empty string, got %v", got)
+	}
+}
+
+func TestLongKey(t *testing.T) {
+	s := New()
+	long := strings.Repeat("x", arenaSize+1)
+	s.PutSlice([]string{"a", long, "b"})
+	if !s.Has(long) || !s.Has("a") || !s.Has("b") {
+		t.Error("Expected a key longer than an arena to be in the set")
+	}
+	if len(s.arenas) != 3 {
+		t.Errorf("Expected 3 arenas, got %v", len(s.arenas))
+	}
+}
+
+// TestRandom checks the set against a map after a random series
+// of puts, deletes and compactions.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := New()
+	model := make(map[string]struct{})
+	for i := 0; i < 20000; i++ {
+		k := fmt.Sprint(r.Intn(2000))
+		switch r.Intn(10) {
+		case 0, 1, 2, 3:
+			s.Delete(k)
+			delete(model, k)
+		case 4:
+			if r.Intn(50) == 0 {
+				s.Compact()
+			}
+		default:
+			s.Put(k)
+			model[k] = struct{}{}
+		}
+		if s.Len() != len(model) {
+			t.Fatalf("Expected %v keys, got %v", len(model), s.Len())
+		}
+	}
+	for i := 0; i < 2000; i++ {
+		k := fmt.Sprint(i)
+		if _, want := model[k]; s.Has(k) != want {
+			t.Fatalf("Expected Has(%q) to be %v", k, want)
+		}
+	}
+	seen := 0
+	s.Iter(func(k string) {
+		if _, ok := model[k]; !ok {
+			t.Fatalf("Did not expect %q in the set", k)
+		}
+		seen++
+	})
+	if seen != len(model) {
+		t.Errorf("Expected to iterate over %v keys, got %v", len(model), seen)
+	}
+}
@@ -0,0 +1,148 @@
+// Package bktree implements a BK-tree, which finds the strings in a
+// set that are within a given edit distance of a word, such as for
+// "did you mean" suggestions.
+//
+// It is an express design decision to hard-code
+// this tree just for the string type rather than for
+// the empty interface.
+//
+// Each child of a node is stored under its distance from the node.
+// Because the distance is a metric, which obeys the triangle
+// inequality, a search for words within distance d of a word w need
+// only visit the children of a node at distance k from w whose
+// distances from the node are in [k-d, k+d], which prunes most of the
+// tree when d is small.
+package bktree
+
+import (
+	"sort"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+// Metric returns the distance between two strings. It must be a
+// metric: never negative, zero only for equal strings, symmetric,
+// and obeying the triangle inequality, or searches will miss words.
+type Metric func(a string, b string) int
+
+// Result is a word found by a search, along with its distance
+// from the word searched for.
+type Result struct {
+	Word     string
+	Distance int
+}
+
+// Tree holds the root of the tree and the metric it is built with.
+type Tree struct {
+	metric Metric
+	root   *node
+	size   int
+}
+
+type node struct {
+	word     string
+	children map[int]*node
+}
+
+// New returns a new empty tree that measures distance using metric,
+// or using Levenshtein if metric is nil.
+func New(metric Metric) *Tree {
+	if metric == nil {
+		metric = Levenshtein
+	}
+	return &Tree{metric: metric}
+}
+
+// FromSet returns a new tree holding the members of s, which
+// measures distance using metric, or using Levenshtein if metric
+// is nil.
+func FromSet(s set.Set, metric Metric) *Tree {
+	words := make([]string, 0, len(s))
+	for w := range s {
+		words = append(words, w)
+	}
+	// Add the words in a fixed order, so that the tree has the
+	// same shape every time, since a set has no order.
+	sort.Strings(words)
+	t := New(metric)
+	for _, w := range words {
+		t.Add(w)
+	}
+	return t
+}
+
+// Len returns the number of words in the tree.
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Add puts word in the tree. It does nothing if word is already
+// in the tree.
+func (t *Tree) Add(word string) {
+	if t.root == nil {
+		t.root = &node{word: word}
+		t.size++
+		return
+	}
+	n := t.root
+	for {
+		d := t.metric(word, n.word)
+		if d == 0 {
+			return
+		}
+		child, ok := n.children[d]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[int]*node)
+			}
+			n.children[d] = &node{word: word}
+			t.size++
+			return
+		}
+		n = child
+	}
+}
+
+// Has tells you whether word is in the tree.
+func (t *Tree) Has(word string) bool {
+	n := t.root
+	for n != nil {
+		d := t.metric(word, n.word)
+		if d == 0 {
+			return true
+		}
+		n = n.children[d]
+	}
+	return false
+}
+
+// Search returns every word in the tree within maxDistance of word,
+// nearest first, and in lexicographic order among words at the same
+// distance.
+func (t *Tree) Search(word string, maxDistance int) []Result {
+	var results []Result
+	if t.root == nil {
+		return results
+	}
+	stack := []*node{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d := t.metric(word, n.word)
+		if d <= maxDistance {
+			results = append(results, Result{Word: n.word, Distance: d})
+		}
+		for k, child := range n.children {
+			if k >= d-maxDistance && k <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Word < results[j].Word
+	})
+	return results
+}
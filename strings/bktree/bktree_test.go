@@ -0,0 +1,103 @@
+package bktree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+func TestSearch(t *testing.T) {
+	words := set.New()
+	words.PutSlice([]string{"book", "books", "cake", "boo", "boon", "cook", "cape", "cart"})
+	tree := FromSet(words, nil)
+	tree.Add("book")
+	if tree.Len() != 8 {
+		t.Errorf("Expected 8 words, got %v", tree.Len())
+	}
+	if !tree.Has("boon") || tree.Has("bo") {
+		t.Error("Membership was not as expected")
+	}
+	want := []Result{
+		{"book", 0},
+		{"boo", 1},
+		{"books", 1},
+		{"boon", 1},
+		{"cook", 1},
+	}
+	if got := tree.Search("book", 1); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := New(nil).Search("book", 2); len(got) != 0 {
+		t.Errorf("Expected no results from an empty tree, got %v", got)
+	}
+}
+
+// TestInvalidUTF8 checks that words that differ only in bytes that
+// are not valid UTF-8 are not taken for the same word.
+func TestInvalidUTF8(t *testing.T) {
+	tree := New(nil)
+	tree.Add("a\xff")
+	tree.Add("a\xfe")
+	tree.Add("a\uFFFD")
+	if tree.Len() != 3 {
+		t.Errorf("Expected 3 words, got %v", tree.Len())
+	}
+	want := []Result{{"a\xfe", 0}, {"a\uFFFD", 1}, {"a\xff", 1}}
+	if got := tree.Search("a\xfe", 1); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestDamerau(t *testing.T) {
+	words := set.New()
+	words.PutSlice([]string{"form", "from", "farm"})
+	tree := FromSet(words, Damerau)
+	want := []Result{{"from", 0}, {"form", 1}}
+	if got := tree.Search("from", 1); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestRandom checks searches against measuring the distance
+// to every word.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomString := func() string {
+		b := make([]byte, 1+r.Intn(7))
+		for i := range b {
+			b[i] = "abcd"[r.Intn(4)]
+		}
+		return string(b)
+	}
+	words := set.New()
+	for i := 0; i < 500; i++ {
+		words.Put(randomString())
+	}
+	for _, metric := range []Metric{Levenshtein, Damerau} {
+		tree := FromSet(words, metric)
+		for i := 0; i < 100; i++ {
+			word := randomString()
+			maxDistance := r.Intn(3)
+			var want []Result
+			for w := range words {
+				if d := metric(word, w); d <= maxDistance {
+					want = append(want, Result{w, d})
+				}
+			}
+			got := tree.Search(word, maxDistance)
+			if len(want) != len(got) {
+				t.Fatalf("Expected %v results for %q, got %v", len(want), word, len(got))
+			}
+			for _, result := range want {
+				if !slices.Contains(got, result) {
+					t.Fatalf("Expected %v among the results for %q", result, word)
+				}
+			}
+			if !slices.IsSortedFunc(got, func(a, b Result) int { return a.Distance - b.Distance }) {
+				t.Fatalf("Results for %q were not sorted by distance", word)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package bktree
+
+import "unicode/utf8"
+
+// labels returns the runes of s, except that each byte that is not
+// part of valid UTF-8 gets a label of its own, -1 minus the byte,
+// rather than becoming utf8.RuneError as it would in []rune(s). That
+// way strings that differ only in their invalid bytes, or in having
+// an invalid byte where the other has U+FFFD, are still a distance
+// apart, as a Metric requires of strings that are not equal.
+func labels(s string) []rune {
+	ls := make([]rune, 0, len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			r = -1 - rune(s[i])
+		}
+		ls = append(ls, r)
+		i += size
+	}
+	return ls
+}
+
+// Levenshtein returns the Levenshtein distance between a and b:
+// the fewest insertions, deletions and substitutions of single runes
+// that turn a into b. Each byte that is not part of valid UTF-8
+// counts as a rune of its own.
+func Levenshtein(a string, b string) int {
+	ra, rb := labels(a), labels(b)
+	// Only two rows of the table are needed at a time.
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Damerau returns the Damerau-Levenshtein distance between a and b:
+// the fewest insertions, deletions and substitutions of single runes,
+// and transpositions of two adjacent runes, that turn a into b.
+// Each byte that is not part of valid UTF-8 counts as a rune of its
+// own.
+//
+// This is the unrestricted distance, in which a substring may be
+// edited again after it is transposed, rather than the optimal string
+// alignment distance that is often computed in its place, which does
+// not obey the triangle inequality, and so is not a Metric.
+func Damerau(a string, b string) int {
+	ra, rb := labels(a), labels(b)
+	// d[i+1][j+1] is the distance between ra[:i] and rb[:j], with an
+	// extra row and column of a distance too large ever to be used.
+	inf := len(ra) + len(rb)
+	d := make([][]int, len(ra)+2)
+	for i := range d {
+		d[i] = make([]int, len(rb)+2)
+	}
+	d[0][0] = inf
+	for i := 0; i <= len(ra); i++ {
+		d[i+1][0] = inf
+		d[i+1][1] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		d[0][j+1] = inf
+		d[1][j+1] = j
+	}
+	// lastRow[r] is the last row in which rune r was seen in a.
+	lastRow := make(map[rune]int)
+	for i := 1; i <= len(ra); i++ {
+		// lastCol is the last column in this row in which
+		// rb matched ra[i-1].
+		lastCol := 0
+		for j := 1; j <= len(rb); j++ {
+			k := lastRow[rb[j-1]]
+			l := lastCol
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+				lastCol = j
+			}
+			d[i+1][j+1] = min(
+				d[i][j]+cost,
+				d[i+1][j]+1,
+				d[i][j+1]+1,
+				// Transpose ra[k-1] and rb[l-1], editing
+				// whatever lies between them.
+				d[k][l]+(i-k-1)+1+(j-l-1),
+			)
+		}
+		lastRow[ra[i-1]] = i
+	}
+	return d[len(ra)+1][len(rb)+1]
+}
@@ -0,0 +1,64 @@
+package bktree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	var tests = []struct {
+		a           string
+		b           string
+		levenshtein int
+		damerau     int
+	}{
+		{"", "", 0, 0},
+		{"", "abc", 3, 3},
+		{"kitten", "sitting", 3, 3},
+		{"flaw", "lawn", 2, 2},
+		{"ab", "ba", 2, 1},
+		{"abc", "ca", 3, 2},
+		{"naïve", "naive", 1, 1},
+		{"éa", "aé", 2, 1},
+		{"\xff", "\xfe", 1, 1},
+		{"\xff", "\uFFFD", 1, 1},
+		{"a\xc3", "a\xc3\xa9", 1, 1},
+		{"\xff\xfe", "\xfe\xff", 2, 1},
+	}
+	for _, test := range tests {
+		if got := Levenshtein(test.a, test.b); got != test.levenshtein {
+			t.Errorf("Expected Levenshtein(%q, %q) to be %v, got %v", test.a, test.b, test.levenshtein, got)
+		}
+		if got := Damerau(test.a, test.b); got != test.damerau {
+			t.Errorf("Expected Damerau(%q, %q) to be %v, got %v", test.a, test.b, test.damerau, got)
+		}
+	}
+}
+
+// TestMetricAxioms checks that both metrics are symmetric and obey
+// the triangle inequality, which the tree relies on.
+func TestMetricAxioms(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomString := func() string {
+		b := make([]byte, r.Intn(6))
+		for i := range b {
+			b[i] = "abc"[r.Intn(3)]
+		}
+		return string(b)
+	}
+	for name, metric := range map[string]Metric{"Levenshtein": Levenshtein, "Damerau": Damerau} {
+		for i := 0; i < 3000; i++ {
+			a, b, c := randomString(), randomString(), randomString()
+			ab, ba := metric(a, b), metric(b, a)
+			if ab != ba {
+				t.Fatalf("%v: distance from %q to %q is %v one way and %v the other", name, a, b, ab, ba)
+			}
+			if (ab == 0) != (a == b) {
+				t.Fatalf("%v: distance from %q to %q is %v", name, a, b, ab)
+			}
+			if ac, bc := metric(a, c), metric(b, c); ac > ab+bc {
+				t.Fatalf("%v: %q, %q and %q break the triangle inequality", name, a, b, c)
+			}
+		}
+	}
+}
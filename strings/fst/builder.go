@@ -0,0 +1,248 @@
+package fst
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+var InvalidFormat = errors.New("Invalid FST Format")
+
+type UnsortedInputError struct {
+	msg string
+}
+
+func (e *UnsortedInputError) Error() string {
+	return e.msg
+}
+
+// magic begins every serialised FST; its last byte is the version
+// of the format.
+const magic = "mmfst\x01"
+
+// trailerSize is the size of the root address and key count at the
+// end of a serialised FST.
+const trailerSize = 16
+
+const flagFinal = 1
+
+type transition struct {
+	label  byte
+	output uint64
+	target uint64
+}
+
+// unfinished is a node on the path of the last key inserted, which
+// may still get more transitions. Its last transition is held apart,
+// because its target has not been written yet.
+type unfinished struct {
+	final       bool
+	finalOutput uint64
+	transitions []transition
+	hasLast     bool
+	lastLabel   byte
+	lastOutput  uint64
+}
+
+// prependOutput adds output to every way out of n, having taken it
+// off the transition into n.
+func (n *unfinished) prependOutput(output uint64) {
+	if n.final {
+		n.finalOutput += output
+	}
+	for i := range n.transitions {
+		n.transitions[i].output += output
+	}
+	if n.hasLast {
+		n.lastOutput += output
+	}
+}
+
+// Builder builds an FST from keys inserted in ascending order.
+//
+// It keeps only the path of the last key inserted in memory, and
+// writes each node out as soon as no later key can reach it,
+// sharing it with any identical node already written, so that the
+// result is the minimal transducer for the keys.
+type Builder struct {
+	data     []byte
+	nodes    []unfinished
+	registry map[string]uint64
+	scratch  []byte
+	last     string
+	n        int
+	finished bool
+}
+
+// NewBuilder returns a new builder with no keys.
+func NewBuilder() *Builder {
+	return &Builder{
+		data:     []byte(magic),
+		nodes:    make([]unfinished, 1),
+		registry: make(map[string]uint64),
+	}
+}
+
+// Insert inserts key, with value, into the FST. Keys must be
+// inserted in strictly ascending order.
+func (b *Builder) Insert(key string, value uint64) error {
+	if b.finished {
+		panic("fst: Insert called after Bytes")
+	}
+	if b.n > 0 && key <= b.last {
+		return &UnsortedInputError{
+			msg: fmt.Sprintf("key %q is not greater than %q", key, b.last),
+		}
+	}
+	prefixLen := 0
+	for prefixLen < len(key) && prefixLen < len(b.last) && key[prefixLen] == b.last[prefixLen] {
+		prefixLen++
+	}
+	if b.n > 0 {
+		b.freeze(prefixLen)
+	}
+
+	for len(b.nodes) <= len(key) {
+		b.nodes = append(b.nodes, unfinished{})
+	}
+	for i := prefixLen; i < len(key); i++ {
+		b.nodes[i].hasLast = true
+		b.nodes[i].lastLabel = key[i]
+		b.nodes[i].lastOutput = 0
+	}
+	b.nodes[len(key)].final = true
+
+	// Leave on each transition of the shared prefix only the part
+	// of its output that every key through it has in common, and
+	// push the rest further along.
+	for i := 0; i < prefixLen; i++ {
+		common := min(b.nodes[i].lastOutput, value)
+		b.nodes[i+1].prependOutput(b.nodes[i].lastOutput - common)
+		b.nodes[i].lastOutput = common
+		value -= common
+	}
+	if prefixLen < len(key) {
+		b.nodes[prefixLen].lastOutput = value
+	} else {
+		// Only the empty key, inserted first, ends at the root.
+		b.nodes[prefixLen].finalOutput = value
+	}
+
+	b.last = key
+	b.n++
+	return nil
+}
+
+// freeze writes out the nodes of the last key that are deeper than
+// depth, which no later key can reach, and hangs each off its
+// parent.
+func (b *Builder) freeze(depth int) {
+	for i := len(b.last); i > depth; i-- {
+		addr := b.compile(&b.nodes[i])
+		b.nodes[i] = unfinished{transitions: b.nodes[i].transitions[:0]}
+		parent := &b.nodes[i-1]
+		parent.transitions = append(parent.transitions, transition{
+			label:  parent.lastLabel,
+			output: parent.lastOutput,
+			target: addr,
+		})
+		parent.hasLast = false
+	}
+}
+
+// compile writes n out, unless an identical node has already been
+// written, and returns its address.
+func (b *Builder) compile(n *unfinished) uint64 {
+	// Nodes are identical if they have the same finality, outputs
+	// and transitions, down to the addresses of their targets.
+	b.scratch = b.scratch[:0]
+	if n.final {
+		b.scratch = append(b.scratch, flagFinal)
+		b.scratch = binary.AppendUvarint(b.scratch, n.finalOutput)
+	} else {
+		b.scratch = append(b.scratch, 0)
+	}
+	for _, t := range n.transitions {
+		b.scratch = append(b.scratch, t.label)
+		b.scratch = binary.AppendUvarint(b.scratch, t.output)
+		b.scratch = binary.AppendUvarint(b.scratch, t.target)
+	}
+	if addr, ok := b.registry[string(b.scratch)]; ok {
+		return addr
+	}
+
+	addr := uint64(len(b.data))
+	b.registry[string(b.scratch)] = addr
+	if n.final {
+		b.data = append(b.data, flagFinal)
+		b.data = binary.AppendUvarint(b.data, n.finalOutput)
+	} else {
+		b.data = append(b.data, 0)
+	}
+	b.data = binary.AppendUvarint(b.data, uint64(len(n.transitions)))
+	for _, t := range n.transitions {
+		b.data = append(b.data, t.label)
+		b.data = binary.AppendUvarint(b.data, t.output)
+		// Children are always written before their parents, so
+		// the distance back to them is positive and usually small.
+		b.data = binary.AppendUvarint(b.data, addr-t.target)
+	}
+	return addr
+}
+
+// Bytes finishes the FST and returns it serialised. The builder
+// cannot be used after Bytes is called.
+func (b *Builder) Bytes() []byte {
+	if !b.finished {
+		if b.n > 0 {
+			b.freeze(0)
+		}
+		root := b.compile(&b.nodes[0])
+		b.data = binary.LittleEndian.AppendUint64(b.data, root)
+		b.data = binary.LittleEndian.AppendUint64(b.data, uint64(b.n))
+		b.finished = true
+		b.nodes = nil
+		b.registry = nil
+	}
+	return b.data
+}
+
+// Build returns an FST mapping each of keys, which must be in
+// strictly ascending order, to the value at the same index in
+// values. If values is nil, every key maps to 0, which makes the
+// FST a plain set.
+func Build(keys []string, values []uint64) (*FST, error) {
+	if values != nil && len(values) != len(keys) {
+		return nil, &UnsortedInputError{
+			msg: fmt.Sprintf("%d keys but %d values", len(keys), len(values)),
+		}
+	}
+	b := NewBuilder()
+	for i, k := range keys {
+		var v uint64
+		if values != nil {
+			v = values[i]
+		}
+		if err := b.Insert(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return Open(b.Bytes())
+}
+
+// FromSet returns an FST holding the members of s, each mapped to 0.
+func FromSet(s set.Set) *FST {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	f, err := Build(keys, nil)
+	if err != nil {
+		panic(fmt.Sprintf("fst: building from a set: %v", err))
+	}
+	return f
+}
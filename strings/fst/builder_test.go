@@ -0,0 +1,85 @@
+package fst
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+func TestUnsorted(t *testing.T) {
+	var tests = []struct {
+		keys   []string
+		values []uint64
+	}{
+		{[]string{"b", "a"}, nil},
+		{[]string{"a", "a"}, nil},
+		{[]string{"ab", "a"}, nil},
+		{[]string{"a", "b"}, []uint64{1}},
+	}
+	for _, test := range tests {
+		_, err := Build(test.keys, test.values)
+		var unsorted *UnsortedInputError
+		if !errors.As(err, &unsorted) {
+			t.Errorf("Expected UnsortedInputError for %v %v, got %v", test.keys, test.values, err)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	f, err := Build(nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.Len() != 0 || f.Contains("") || f.Contains("a") {
+		t.Error("Expected an empty FST")
+	}
+	f, err = Build([]string{""}, []uint64{7})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if v, ok := f.Get(""); !ok || v != 7 {
+		t.Errorf("Expected the empty key to map to 7, got %v, %v", v, ok)
+	}
+}
+
+// TestShared checks that keys with the same suffixes share nodes,
+// so that many keys take far less space than their bytes.
+func TestShared(t *testing.T) {
+	s := set.New()
+	total := 0
+	for _, a := range []string{"re", "un", "over", "under", "pre", "mis", ""} {
+		for _, b := range []string{"do", "make", "take", "write", "build", "run"} {
+			for _, c := range []string{"", "s", "ing", "able", "er", "ers"} {
+				s.Put(a + b + c)
+				total += len(a + b + c)
+			}
+		}
+	}
+	f := FromSet(s)
+	if f.Len() != len(s) {
+		t.Errorf("Expected %v keys, got %v", len(s), f.Len())
+	}
+	if size := len(f.Bytes()); size*4 > total {
+		t.Errorf("Expected %v bytes of keys to take under a quarter of that, got %v", total, size)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	b := NewBuilder()
+	b.Insert("cat", 1)
+	b.Insert("dog", 2)
+	data := b.Bytes()
+	f, err := Open(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if v, ok := f.Get("dog"); !ok || v != 2 {
+		t.Errorf("Expected dog to map to 2, got %v, %v", v, ok)
+	}
+	for _, bad := range [][]byte{nil, data[:10], append([]byte("xxxxxx"), data[6:]...)} {
+		if _, err := Open(bad); err != InvalidFormat {
+			t.Errorf("Expected InvalidFormat, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package fst implements an immutable, compressed map from strings
+// to uint64 values as a finite-state transducer.
+//
+// It is an express design decision to hard-code
+// this map just for the string type rather than for
+// the empty interface.
+//
+// An FST is a minimal acyclic automaton over the bytes of its keys:
+// keys share not only their prefixes, as in a trie, but also their
+// suffixes. Each transition carries part of a value, and the value
+// of a key is the sum of the outputs along its path. For a large
+// dictionary, that typically takes a small fraction of the space of
+// the keys themselves.
+//
+// An FST is built by a Builder from keys in ascending order, and
+// serialised to a byte slice as it is built. Open reads an FST
+// straight out of such a slice, without decoding it, so the slice
+// can just as well be a file mapped into memory. Because every key
+// maps to some value, an FST whose values are all 0 serves as a
+// set, with Contains.
+package fst
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// FST is a serialised transducer, read in place.
+type FST struct {
+	data []byte
+	root int
+	n    int
+}
+
+// node is a node decoded from the data, apart from its transitions,
+// which start at offset trans.
+type node struct {
+	addr        int
+	final       bool
+	finalOutput uint64
+	numTrans    int
+	trans       int
+}
+
+// Open returns the FST serialised in data, which must have come from
+// Builder.Bytes. It only checks the header and trailer, so data that
+// has been corrupted may make later calls panic. The FST uses data
+// directly, so data must not be changed afterwards.
+func Open(data []byte) (*FST, error) {
+	if len(data) < len(magic)+trailerSize || !bytes.HasPrefix(data, []byte(magic)) {
+		return nil, InvalidFormat
+	}
+	trailer := data[len(data)-trailerSize:]
+	root := binary.LittleEndian.Uint64(trailer)
+	n := binary.LittleEndian.Uint64(trailer[8:])
+	if root < uint64(len(magic)) || root >= uint64(len(data)-trailerSize) || n > uint64(len(data)) {
+		return nil, InvalidFormat
+	}
+	return &FST{data: data, root: int(root), n: int(n)}, nil
+}
+
+// Bytes returns the FST serialised, as given to Open.
+func (f *FST) Bytes() []byte {
+	return f.data
+}
+
+// Len returns the number of keys in the FST.
+func (f *FST) Len() int {
+	return f.n
+}
+
+func (f *FST) node(addr int) node {
+	nd := node{addr: addr, final: f.data[addr]&flagFinal != 0}
+	i := addr + 1
+	if nd.final {
+		out, w := binary.Uvarint(f.data[i:])
+		nd.finalOutput = out
+		i += w
+	}
+	numTrans, w := binary.Uvarint(f.data[i:])
+	nd.numTrans = int(numTrans)
+	nd.trans = i + w
+	return nd
+}
+
+// transition decodes the transition at offset i, and returns it
+// with the offset of the next one.
+func (f *FST) transition(nd node, i int) (transition, int) {
+	t := transition{label: f.data[i]}
+	i++
+	out, w := binary.Uvarint(f.data[i:])
+	t.output = out
+	i += w
+	delta, w := binary.Uvarint(f.data[i:])
+	t.target = uint64(nd.addr) - delta
+	return t, i + w
+}
+
+// step follows the transition out of nd labelled c, if there is one.
+func (f *FST) step(nd node, c byte) (transition, bool) {
+	i := nd.trans
+	for j := 0; j < nd.numTrans; j++ {
+		var t transition
+		t, i = f.transition(nd, i)
+		if t.label == c {
+			return t, true
+		}
+		if t.label > c {
+			break
+		}
+	}
+	return transition{}, false
+}
+
+// walk follows the path spelled by s from the root, and returns the
+// node it ends at with the sum of the outputs along the way.
+func (f *FST) walk(s string) (node, uint64, bool) {
+	nd := f.node(f.root)
+	var out uint64
+	for i := 0; i < len(s); i++ {
+		t, ok := f.step(nd, s[i])
+		if !ok {
+			return node{}, 0, false
+		}
+		out += t.output
+		nd = f.node(int(t.target))
+	}
+	return nd, out, true
+}
+
+// Get returns the value of key, and whether key is in the FST.
+func (f *FST) Get(key string) (uint64, bool) {
+	nd, out, ok := f.walk(key)
+	if !ok || !nd.final {
+		return 0, false
+	}
+	return out + nd.finalOutput, true
+}
+
+// Contains tells you whether key is in the FST.
+func (f *FST) Contains(key string) bool {
+	_, ok := f.Get(key)
+	return ok
+}
+
+// Iter iterates through every key in the FST in ascending order,
+// and calls function f using the key and its value as arguments.
+func (f *FST) Iter(fn func(key string, value uint64)) {
+	f.Range("", "", fn)
+}
+
+// IterPrefix iterates through every key in the FST that starts with
+// prefix, in ascending order, and calls function f using the key and
+// its value as arguments.
+func (f *FST) IterPrefix(prefix string, fn func(key string, value uint64)) {
+	nd, out, ok := f.walk(prefix)
+	if !ok {
+		return
+	}
+	f.iter(nd, []byte(prefix), out, "", "", fn)
+}
+
+// Range iterates through every key k in the FST with lo <= k < hi,
+// in ascending order, and calls function f using the key and its
+// value as arguments. An empty hi means there is no upper bound.
+func (f *FST) Range(lo, hi string, fn func(key string, value uint64)) {
+	f.iter(f.node(f.root), nil, 0, lo, hi, fn)
+}
+
+// iter visits the keys below nd, all of which start with key, and
+// returns false once it has passed hi.
+func (f *FST) iter(nd node, key []byte, out uint64, lo, hi string, fn func(key string, value uint64)) bool {
+	if nd.final && string(key) >= lo {
+		fn(string(key), out+nd.finalOutput)
+	}
+	i := nd.trans
+	for j := 0; j < nd.numTrans; j++ {
+		var t transition
+		t, i = f.transition(nd, i)
+		next := append(key, t.label)
+		// Every key below this transition starts with next, so if
+		// next is below the same length prefix of lo, they are all
+		// below lo, and if next is at least hi, they are all at
+		// least hi, as are the keys below the later transitions.
+		if string(next) < lo[:min(len(lo), len(next))] {
+			continue
+		}
+		if hi != "" && string(next) >= hi {
+			return false
+		}
+		if !f.iter(f.node(int(t.target)), next, out+t.output, lo, hi, fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package fst
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type entry struct {
+	key   string
+	value uint64
+}
+
+func collect(iter func(fn func(string, uint64))) []entry {
+	var got []entry
+	iter(func(k string, v uint64) {
+		got = append(got, entry{k, v})
+	})
+	return got
+}
+
+func TestQueries(t *testing.T) {
+	keys := []string{"", "mon", "mond", "monday", "moon", "tues", "tuesday", "wed"}
+	values := []uint64{9, 3, 40, 1, 1, 2, 200, 5}
+	f, err := Build(keys, values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i, k := range keys {
+		if v, ok := f.Get(k); !ok || v != values[i] {
+			t.Errorf("Expected %q to map to %v, got %v, %v", k, values[i], v, ok)
+		}
+	}
+	for _, k := range []string{"m", "mo", "monda", "mondays", "x"} {
+		if f.Contains(k) {
+			t.Errorf("Did not expect %q in the FST", k)
+		}
+	}
+	var tests = []struct {
+		name string
+		got  []entry
+		want []entry
+	}{
+		{
+			"IterPrefix(mon)",
+			collect(func(fn func(string, uint64)) { f.IterPrefix("mon", fn) }),
+			[]entry{{"mon", 3}, {"mond", 40}, {"monday", 1}},
+		},
+		{
+			"IterPrefix(x)",
+			collect(func(fn func(string, uint64)) { f.IterPrefix("x", fn) }),
+			nil,
+		},
+		{
+			"Range(mond, tuesday)",
+			collect(func(fn func(string, uint64)) { f.Range("mond", "tuesday", fn) }),
+			[]entry{{"mond", 40}, {"monday", 1}, {"moon", 1}, {"tues", 2}},
+		},
+		{
+			"Range(n, )",
+			collect(func(fn func(string, uint64)) { f.Range("n", "", fn) }),
+			[]entry{{"tues", 2}, {"tuesday", 200}, {"wed", 5}},
+		},
+	}
+	for _, test := range tests {
+		if !slices.Equal(test.want, test.got) {
+			t.Errorf("%v: expected %v, got %v", test.name, test.want, test.got)
+		}
+	}
+}
+
+// TestRandom checks lookups and iteration against a sorted slice.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomString := func() string {
+		b := make([]byte, r.Intn(8))
+		for i := range b {
+			b[i] = "abc\xff"[r.Intn(4)]
+		}
+		return string(b)
+	}
+	seen := make(map[string]bool)
+	var keys []string
+	for i := 0; i < 2000; i++ {
+		if k := randomString(); !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	values := make([]uint64, len(keys))
+	want := make([]entry, len(keys))
+	for i, k := range keys {
+		values[i] = uint64(r.Intn(1000))
+		want[i] = entry{k, values[i]}
+	}
+	f, err := Build(keys, values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := collect(f.Iter); !slices.Equal(want, got) {
+		t.Fatalf("Iter did not visit every key in order")
+	}
+	for i := 0; i < 200; i++ {
+		k := randomString()
+		if v, ok := f.Get(k); ok != seen[k] || (ok && v != values[slices.Index(keys, k)]) {
+			t.Fatalf("Get(%q) was not as expected", k)
+		}
+		lo, hi := randomString(), randomString()
+		var inRange, withPrefix []entry
+		for _, e := range want {
+			if e.key >= lo && (hi == "" || e.key < hi) {
+				inRange = append(inRange, e)
+			}
+			if strings.HasPrefix(e.key, lo) {
+				withPrefix = append(withPrefix, e)
+			}
+		}
+		if got := collect(func(fn func(string, uint64)) { f.Range(lo, hi, fn) }); !slices.Equal(inRange, got) {
+			t.Fatalf("Range(%q, %q): expected %v, got %v", lo, hi, inRange, got)
+		}
+		if got := collect(func(fn func(string, uint64)) { f.IterPrefix(lo, fn) }); !slices.Equal(withPrefix, got) {
+			t.Fatalf("IterPrefix(%q): expected %v, got %v", lo, withPrefix, got)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package fst
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Result is a key found by Search, with its value and its
+// Levenshtein distance from the word searched for.
+type Result struct {
+	Key      string
+	Value    uint64
+	Distance int
+}
+
+// levenshtein is an automaton that accepts the strings within max
+// edits of word. Each state is a row of the Levenshtein distance
+// matrix: state[i] is the distance from the first i runes of word
+// to the runes read so far.
+type levenshtein struct {
+	word []rune
+	max  int
+}
+
+func (l *levenshtein) start() []int {
+	state := make([]int, len(l.word)+1)
+	for i := range state {
+		state[i] = i
+	}
+	return state
+}
+
+func (l *levenshtein) step(state []int, r rune) []int {
+	next := make([]int, len(state))
+	next[0] = state[0] + 1
+	for i := 1; i < len(state); i++ {
+		cost := 1
+		if l.word[i-1] == r {
+			cost = 0
+		}
+		next[i] = min(next[i-1]+1, state[i]+1, state[i-1]+cost)
+	}
+	return next
+}
+
+// distance returns the distance from word to the runes read to
+// reach state.
+func (l *levenshtein) distance(state []int) int {
+	return state[len(state)-1]
+}
+
+// canMatch tells you whether any string starting with the runes
+// read to reach state could be within max edits of word.
+func (l *levenshtein) canMatch(state []int) bool {
+	return slices.Min(state) <= l.max
+}
+
+// Search returns every key within maxDistance edits of word, by
+// Levenshtein distance counted in runes, sorted by distance and
+// then by key. Each byte that is not part of valid UTF-8 counts as
+// a rune of its own, distinct from every other byte and from
+// U+FFFD.
+//
+// Rather than measuring the distance to every key, it runs the FST
+// in step with a Levenshtein automaton for word, and abandons each
+// path through the FST as soon as the automaton shows that no key
+// along it can be close enough.
+func (f *FST) Search(word string, maxDistance int) []Result {
+	if maxDistance < 0 {
+		return nil
+	}
+	var runes []rune
+	for b := []byte(word); len(b) > 0; {
+		r, size := label(b)
+		runes = append(runes, r)
+		b = b[size:]
+	}
+	l := &levenshtein{word: runes, max: maxDistance}
+	var results []Result
+	f.search(l, f.node(f.root), nil, 0, l.start(), 0, &results)
+	slices.SortFunc(results, func(a, b Result) int {
+		if a.Distance != b.Distance {
+			return a.Distance - b.Distance
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return results
+}
+
+// search visits the keys below nd, all of which start with key.
+// The last pending bytes of key do not yet make up a whole rune,
+// and so have not been fed to the automaton, which is in state.
+func (f *FST) search(l *levenshtein, nd node, key []byte, out uint64, state []int, pending int, results *[]Result) {
+	if nd.final {
+		// A key may end part way through a rune if it is not
+		// valid UTF-8, in which case each stray byte counts as a
+		// rune of its own.
+		final, _ := feed(l, state, key[len(key)-pending:], true)
+		if d := l.distance(final); d <= l.max {
+			*results = append(*results, Result{
+				Key:      string(key),
+				Value:    out + nd.finalOutput,
+				Distance: d,
+			})
+		}
+	}
+	i := nd.trans
+	for j := 0; j < nd.numTrans; j++ {
+		var t transition
+		t, i = f.transition(nd, i)
+		next := append(key, t.label)
+		nextState, unread := feed(l, state, next[len(next)-pending-1:], false)
+		if !l.canMatch(nextState) {
+			continue
+		}
+		f.search(l, f.node(int(t.target)), next, out+t.output, nextState, len(unread), results)
+	}
+}
+
+// feed feeds the automaton, in state, the runes at the start of b,
+// and returns the new state with the bytes it did not feed. Unless
+// all is true, it stops at an incomplete rune at the end of b.
+func feed(l *levenshtein, state []int, b []byte, all bool) ([]int, []byte) {
+	for len(b) > 0 && (all || utf8.FullRune(b)) {
+		r, size := label(b)
+		state = l.step(state, r)
+		b = b[size:]
+	}
+	return state, b
+}
+
+// label decodes the rune at the start of b. A byte that is not
+// part of valid UTF-8 gets a negative label of its own, which no
+// rune can have.
+func label(b []byte) (rune, int) {
+	r, size := utf8.DecodeRune(b)
+	if r == utf8.RuneError && size == 1 {
+		return -1 - rune(b[0]), 1
+	}
+	return r, size
+}
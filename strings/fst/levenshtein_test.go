@@ -0,0 +1,104 @@
+package fst
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+func TestSearch(t *testing.T) {
+	words := set.New()
+	words.PutSlice([]string{"book", "books", "cake", "boo", "boon", "cook", "cape", "cart", "bøøk", "b\xff", "b\xfe", "b\uFFFD"})
+	f := FromSet(words)
+	want := []Result{
+		{"book", 0, 0},
+		{"boo", 0, 1},
+		{"books", 0, 1},
+		{"boon", 0, 1},
+		{"cook", 0, 1},
+	}
+	if got := f.Search("book", 1); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	want = []Result{{"bøøk", 0, 0}, {"book", 0, 2}}
+	if got := f.Search("bøøk", 2); !slices.Equal(want, got[:2]) {
+		t.Errorf("Expected distances counted in runes, got %v", got)
+	}
+	want = []Result{{"b\xff", 0, 0}, {"b\uFFFD", 0, 1}, {"b\xfe", 0, 1}}
+	if got := f.Search("b\xff", 1); !slices.Equal(want, got[:3]) {
+		t.Errorf("Expected each invalid byte to count as a distinct rune, got %v", got)
+	}
+	if got := f.Search("book", -1); got != nil {
+		t.Errorf("Expected no results, got %v", got)
+	}
+}
+
+// distance is the Levenshtein distance from a to b, worked out the
+// slow way, with each invalid byte counted as a rune of its own.
+func distance(a, b string) int {
+	runes := func(s string) []rune {
+		var rs []rune
+		for b := []byte(s); len(b) > 0; {
+			r, size := label(b)
+			rs = append(rs, r)
+			b = b[size:]
+		}
+		return rs
+	}
+	ra, rb := runes(a), runes(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := range ra {
+		prev := row[0]
+		row[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			prev, row[j+1] = row[j+1], min(row[j+1]+1, row[j]+1, prev+cost)
+		}
+	}
+	return row[len(rb)]
+}
+
+// TestSearchRandom checks searches against measuring the distance
+// to every key, including keys that are not valid UTF-8.
+func TestSearchRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomString := func() string {
+		var b []byte
+		for n := r.Intn(7); n > 0; n-- {
+			b = append(b, []string{"a", "b", "é", "\xc3", "\xa9", "日"}[r.Intn(6)]...)
+		}
+		return string(b)
+	}
+	words := set.New()
+	for i := 0; i < 500; i++ {
+		words.Put(randomString())
+	}
+	f := FromSet(words)
+	for i := 0; i < 100; i++ {
+		word := randomString()
+		maxDistance := r.Intn(3)
+		var want []Result
+		for w := range words {
+			if d := distance(word, w); d <= maxDistance {
+				want = append(want, Result{w, 0, d})
+			}
+		}
+		got := f.Search(word, maxDistance)
+		if len(want) != len(got) {
+			t.Fatalf("Expected %v results for %q, got %v", len(want), word, len(got))
+		}
+		for _, result := range want {
+			if !slices.Contains(got, result) {
+				t.Fatalf("Expected %v among the results for %q", result, word)
+			}
+		}
+	}
+}
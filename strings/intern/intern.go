@@ -0,0 +1,168 @@
+// Package intern implements a string interning table, which keeps
+// one canonical copy of each distinct string, and numbers them.
+//
+// It is an express design decision to hard-code
+// this table just for the string type rather than for
+// the empty interface.
+//
+// Where strings/set.Set only tells you whether a string is a member,
+// a Table hands back its own copy of each string, so that a program
+// that reads the same strings over and over can drop its own copies
+// and keep only the canonical one. It also gives each distinct string
+// a small integer Symbol, which is cheaper to store, hash and compare
+// than the string, and can be turned back into the string.
+//
+// A Table is safe for concurrent use by multiple goroutines. Strings
+// that are already interned, which is the common case, only need a
+// read lock.
+package intern
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Symbol stands for a distinct string in a Table. Symbols are
+// numbered from 0 in the order their strings were first interned.
+type Symbol uint32
+
+// Stats reports how much interning has saved.
+type Stats struct {
+	// Distinct is the number of distinct strings in the table.
+	Distinct int
+	// Bytes is the number of bytes in the distinct strings.
+	Bytes int
+	// Calls is the number of times a string has been interned.
+	Calls int64
+	// Hits is the number of those times that the string was
+	// already in the table.
+	Hits int64
+	// BytesSaved is the number of bytes in the strings interned
+	// that were already in the table, whose copies the caller could
+	// drop in favour of the canonical copy.
+	BytesSaved int64
+}
+
+// Table holds the canonical copy of each distinct string, indexed
+// both by the string and by its symbol.
+type Table struct {
+	mu      sync.RWMutex
+	symbols map[string]Symbol
+	strs    []string
+	bytes   int
+
+	calls      atomic.Int64
+	hits       atomic.Int64
+	bytesSaved atomic.Int64
+}
+
+// New returns a new empty table.
+func New() *Table {
+	return &Table{
+		symbols: make(map[string]Symbol),
+	}
+}
+
+// NewWithCapacity returns a new empty table with room for
+// capacity distinct strings without growing.
+func NewWithCapacity(capacity int) *Table {
+	return &Table{
+		symbols: make(map[string]Symbol, capacity),
+		strs:    make([]string, 0, capacity),
+	}
+}
+
+// Intern returns the canonical copy of s, interning s if it is
+// not already in the table.
+func (t *Table) Intern(s string) string {
+	return t.Lookup(t.InternSymbol(s))
+}
+
+// InternSymbol returns the symbol for s, interning s if it is
+// not already in the table.
+func (t *Table) InternSymbol(s string) Symbol {
+	t.calls.Add(1)
+	t.mu.RLock()
+	sym, ok := t.symbols[s]
+	t.mu.RUnlock()
+	if ok {
+		t.hit(s)
+		return sym
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Another goroutine may have interned s since the read lock
+	// was released.
+	if sym, ok := t.symbols[s]; ok {
+		t.hit(s)
+		return sym
+	}
+	// Copy s, so that the table does not keep alive whatever
+	// larger string or buffer s may be a slice of.
+	s = strings.Clone(s)
+	sym = Symbol(len(t.strs))
+	t.symbols[s] = sym
+	t.strs = append(t.strs, s)
+	t.bytes += len(s)
+	return sym
+}
+
+func (t *Table) hit(s string) {
+	t.hits.Add(1)
+	t.bytesSaved.Add(int64(len(s)))
+}
+
+// SymbolOf returns the symbol for s, and whether s is in the table.
+// Unlike InternSymbol, it does not intern s.
+func (t *Table) SymbolOf(s string) (Symbol, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	sym, ok := t.symbols[s]
+	return sym, ok
+}
+
+// Has tells you whether s is in the table.
+func (t *Table) Has(s string) bool {
+	_, ok := t.SymbolOf(s)
+	return ok
+}
+
+// Lookup returns the canonical copy of the string for sym. It
+// returns the empty string if no string has that symbol.
+func (t *Table) Lookup(sym Symbol) string {
+	s, _ := t.LookupOK(sym)
+	return s
+}
+
+// LookupOK returns the canonical copy of the string for sym, and
+// whether any string has that symbol.
+func (t *Table) LookupOK(sym Symbol) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if int(sym) >= len(t.strs) {
+		return "", false
+	}
+	return t.strs[sym], true
+}
+
+// Len returns the number of distinct strings in the table.
+func (t *Table) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.strs)
+}
+
+// Stats returns how much interning has saved so far.
+func (t *Table) Stats() Stats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return Stats{
+		Distinct:   len(t.strs),
+		Bytes:      t.bytes,
+		Calls:      t.calls.Load(),
+		Hits:       t.hits.Load(),
+		BytesSaved: t.bytesSaved.Load(),
+	}
+}
@@ -0,0 +1,79 @@
+package intern
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestIntern(t *testing.T) {
+	table := New()
+	a := table.Intern(string([]byte("label")))
+	b := table.Intern(string([]byte("label")))
+	if a != "label" || b != "label" {
+		t.Errorf("Expected label, got %q and %q", a, b)
+	}
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Error("Expected both copies of label to be the canonical copy")
+	}
+	var tests = []struct {
+		s    string
+		want Symbol
+	}{
+		{"label", 0},
+		{"other", 1},
+		{"", 2},
+		{"other", 1},
+	}
+	for _, test := range tests {
+		if got := table.InternSymbol(test.s); got != test.want {
+			t.Errorf("Expected symbol %v for %q, got %v", test.want, test.s, got)
+		}
+	}
+	if s, ok := table.LookupOK(1); !ok || s != "other" {
+		t.Errorf("Expected other, got %q", s)
+	}
+	if _, ok := table.LookupOK(3); ok {
+		t.Error("Did not expect a string for symbol 3")
+	}
+	if _, ok := table.SymbolOf("missing"); ok || table.Has("missing") {
+		t.Error("Did not expect missing to be in the table")
+	}
+	if table.Len() != 3 {
+		t.Errorf("Expected 3 strings, got %v", table.Len())
+	}
+	want := Stats{Distinct: 3, Bytes: 10, Calls: 6, Hits: 3, BytesSaved: 15}
+	if got := table.Stats(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	table := NewWithCapacity(100)
+	var wg sync.WaitGroup
+	symbols := make([][]Symbol, 8)
+	for g := range symbols {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				symbols[g] = append(symbols[g], table.InternSymbol(fmt.Sprint(i%100)))
+			}
+		}(g)
+	}
+	wg.Wait()
+	if table.Len() != 100 {
+		t.Errorf("Expected 100 strings, got %v", table.Len())
+	}
+	for g := range symbols {
+		for i, sym := range symbols[g] {
+			if s := table.Lookup(sym); s != fmt.Sprint(i%100) {
+				t.Fatalf("Expected symbol %v to be %v, got %v", sym, i%100, s)
+			}
+		}
+	}
+	if stats := table.Stats(); stats.Calls != 8000 || stats.Hits != 7900 {
+		t.Errorf("Expected 8000 calls and 7900 hits, got %+v", stats)
+	}
+}
@@ -0,0 +1,129 @@
+// Package normset implements a set for strings that treats strings
+// as equal when they normalise to the same key, such as strings that
+// differ only in case or in Unicode normalisation form.
+//
+// It is an express design decision to hard-code
+// this set just for the string type rather than for
+// the empty interface.
+//
+// Each string put in the set is passed through a Normaliser, and the
+// result is the key the set actually stores, along with the original
+// spelling of the first string put in the set with that key. The
+// built-in normalisers can be chained, so that, for instance,
+//
+//	normset.New(normset.Chain(normset.NFKC, normset.CaseFold))
+//
+// treats "Ｆｏｏ", "foo" and "FOO" as the same member.
+package normset
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normaliser turns a string into the key that the set stores it
+// under. Strings with the same key are the same member of the set.
+type Normaliser func(s string) string
+
+// CaseFold folds s using full Unicode case folding, so that
+// strings that differ only in case, such as "Straße" and
+// "STRASSE", have the same key.
+func CaseFold(s string) string {
+	// A Caser holds state, so one cannot be shared between
+	// goroutines; making a new one each time is cheap.
+	return cases.Fold().String(s)
+}
+
+// NFC puts s into Unicode normalisation form C, so that strings
+// that differ only in whether accents are precomposed, such as
+// "é" and "é", have the same key.
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NFKC puts s into Unicode normalisation form KC, which is like
+// NFC, but also gives compatibility characters, such as ligatures
+// and full-width letters, the same key as the characters they
+// stand for.
+func NFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// Chain returns a Normaliser that applies each of normalisers
+// in turn.
+func Chain(normalisers ...Normaliser) Normaliser {
+	return func(s string) string {
+		for _, n := range normalisers {
+			s = n(s)
+		}
+		return s
+	}
+}
+
+// Set maps the key of each member to the original spelling of
+// the first string put in the set with that key.
+type Set struct {
+	normalise Normaliser
+	members   map[string]string
+}
+
+// New returns a new empty set that stores strings under the
+// keys normalise gives them. If normalise is nil, strings are
+// their own keys, as for strings/set.Set.
+func New(normalise Normaliser) *Set {
+	if normalise == nil {
+		normalise = func(s string) string { return s }
+	}
+	return &Set{
+		normalise: normalise,
+		members:   make(map[string]string),
+	}
+}
+
+func (s *Set) Has(k string) bool {
+	_, ok := s.members[s.normalise(k)]
+	return ok
+}
+
+// Put puts k in the set. If a string with the same key is already
+// in the set, the set keeps remembering that string's spelling.
+func (s *Set) Put(k string) {
+	key := s.normalise(k)
+	if _, ok := s.members[key]; !ok {
+		s.members[key] = k
+	}
+}
+
+// Delete deletes the member with the same key as k, whatever
+// its spelling.
+func (s *Set) Delete(k string) {
+	delete(s.members, s.normalise(k))
+}
+
+func (s *Set) PutSlice(ks []string) {
+	for _, k := range ks {
+		s.Put(k)
+	}
+}
+
+// Original returns the spelling of the member with the same key
+// as k, as it was first put in the set. It returns false if there
+// is no such member.
+func (s *Set) Original(k string) (string, bool) {
+	original, ok := s.members[s.normalise(k)]
+	return original, ok
+}
+
+// Len returns the number of members of the set.
+func (s *Set) Len() int {
+	return len(s.members)
+}
+
+// Iter iterates through every member of the set, in no particular
+// order, and calls function f using the original spelling of the
+// member as an argument.
+func (s *Set) Iter(f func(k string)) {
+	for _, original := range s.members {
+		f(original)
+	}
+}
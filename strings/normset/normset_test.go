@@ -0,0 +1,71 @@
+package normset
+
+import (
+	"testing"
+)
+
+func TestNormalisers(t *testing.T) {
+	var tests = []struct {
+		name string
+		n    Normaliser
+		a    string
+		b    string
+	}{
+		{"CaseFold", CaseFold, "Straße", "STRASSE"},
+		{"CaseFold", CaseFold, "ΣΊΣΥΦΟΣ", "σίσυφος"},
+		{"NFC", NFC, "café", "café"},
+		{"NFKC", NFKC, "ﬁle", "file"},
+		{"NFKC", NFKC, "Ｆｏｏ", "Foo"},
+		{"Chain", Chain(NFKC, CaseFold), "Ｆｏｏ", "foo"},
+	}
+	for _, test := range tests {
+		if a, b := test.n(test.a), test.n(test.b); a != b {
+			t.Errorf("%v: expected %q and %q to have the same key, got %q and %q", test.name, test.a, test.b, a, b)
+		}
+	}
+	if NFC("ﬁle") == NFC("file") {
+		t.Error("Did not expect NFC to fold a ligature")
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := New(Chain(NFKC, CaseFold))
+	s.PutSlice([]string{"Alice", "ALICE", "Ｂｏｂ"})
+	s.Put("bob")
+	if s.Len() != 2 {
+		t.Errorf("Expected 2 members, got %v", s.Len())
+	}
+	for _, k := range []string{"alice", "aLiCe", "BOB", "Ｂｏｂ"} {
+		if !s.Has(k) {
+			t.Errorf("Expected %q to be in the set", k)
+		}
+	}
+	if original, ok := s.Original("alice"); !ok || original != "Alice" {
+		t.Errorf("Expected Alice, got %q", original)
+	}
+	if original, ok := s.Original("BOB"); !ok || original != "Ｂｏｂ" {
+		t.Errorf("Expected Ｂｏｂ, got %q", original)
+	}
+	s.Delete("ALICE")
+	if s.Has("Alice") {
+		t.Error("Did not expect Alice to be in the set")
+	}
+	if _, ok := s.Original("Alice"); ok {
+		t.Error("Did not expect to find the spelling of Alice")
+	}
+	var got []string
+	s.Iter(func(k string) {
+		got = append(got, k)
+	})
+	if len(got) != 1 || got[0] != "Ｂｏｂ" {
+		t.Errorf("Expected Ｂｏｂ, got %v", got)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	s := New(nil)
+	s.Put("Foo")
+	if s.Has("foo") || !s.Has("Foo") {
+		t.Error("Expected a set without a normaliser to compare raw strings")
+	}
+}
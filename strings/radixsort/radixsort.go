@@ -0,0 +1,83 @@
+// Package radixsort implements an MSD radix sort for strings.
+//
+// It is an express design decision to hard-code
+// this sort just for the string type rather than for
+// the empty interface.
+//
+// The sort buckets the strings by their first byte, then
+// recursively buckets each bucket by the second byte, and so on.
+// Strings that run out of bytes go in a bucket ahead of all the
+// others, so that a string sorts before every string it is a
+// prefix of. Small buckets are finished off with insertion sort,
+// where the cost of counting 256 buckets is not worth paying.
+package radixsort
+
+// insertionSortThreshold is the bucket size at or below which
+// Sort switches to insertion sort.
+const insertionSortThreshold = 32
+
+// Sort performs a radix sort on the provided slice of string,
+// ordering strings byte-wise, the same way as the < operator.
+// Unlike maxheap.Sort, it sorts the whole slice, including index 0.
+func Sort(data []string) {
+	if len(data) < 2 {
+		return
+	}
+	buf := make([]string, len(data))
+	sort(data, buf, 0)
+}
+
+// sort sorts data, all of whose strings share their first depth
+// bytes, using buf (which is the same length as data) as scratch.
+func sort(data []string, buf []string, depth int) {
+	if len(data) <= insertionSortThreshold {
+		insertionSort(data, depth)
+		return
+	}
+	// Bucket 0 is for strings that have no byte at depth;
+	// bucket b+1 is for strings whose byte at depth is b.
+	var counts [257]int
+	for _, s := range data {
+		counts[digit(s, depth)]++
+	}
+	var starts [257]int
+	offset := 0
+	for b, c := range counts {
+		starts[b] = offset
+		offset += c
+	}
+	next := starts
+	for _, s := range data {
+		d := digit(s, depth)
+		buf[next[d]] = s
+		next[d]++
+	}
+	copy(data, buf)
+	// Strings in bucket 0 are all equal, so only the other
+	// buckets need sorting on the next byte.
+	for b := 1; b < len(counts); b++ {
+		if counts[b] > 1 {
+			lo, hi := starts[b], starts[b]+counts[b]
+			sort(data[lo:hi], buf[lo:hi], depth+1)
+		}
+	}
+}
+
+// digit returns the bucket for s at depth: 0 if s is too short
+// to have a byte at depth, or that byte plus one.
+func digit(s string, depth int) int {
+	if depth >= len(s) {
+		return 0
+	}
+	return int(s[depth]) + 1
+}
+
+// insertionSort sorts data, all of whose strings share their
+// first depth bytes, so only the rest of each string is compared.
+func insertionSort(data []string, depth int) {
+	for i := 1; i < len(data); i++ {
+		for j := i; j > 0 && data[j][depth:] < data[j-1][depth:]; j-- {
+			data[j], data[j-1] = data[j-1], data[j]
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package radixsort
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/strings/maxheap"
+)
+
+func TestSort(t *testing.T) {
+	var tests = []struct {
+		input []string
+		want  []string
+	}{
+		{[]string{"606", "243", "737", "864", "", "663", "114", "633", "390", "143", "725", "679"},
+			[]string{"", "114", "143", "243", "390", "606", "633", "663", "679", "725", "737", "864"}},
+		{[]string{"abc", "ab", "a", "abcd", "b", "ab"},
+			[]string{"a", "ab", "ab", "abc", "abcd", "b"}},
+		{[]string{"\xff", "\x00", "z"},
+			[]string{"\x00", "z", "\xff"}},
+		{[]string{"0"},
+			[]string{"0"}},
+		{nil,
+			nil},
+	}
+	for _, test := range tests {
+		Sort(test.input)
+		if !slices.Equal(test.want, test.input) {
+			t.Errorf("Expected %q, got %q", test.want, test.input)
+		}
+	}
+}
+
+func randomStrings(n int) []string {
+	r := rand.New(rand.NewSource(1))
+	data := make([]string, n)
+	for i := range data {
+		// Plenty of shared prefixes, and some strings that are
+		// prefixes of others.
+		data[i] = "key-" + strconv.Itoa(r.Intn(n)) + string(rune('a' + r.Intn(3)))[:r.Intn(2)]
+	}
+	return data
+}
+
+func TestSortRandom(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		input := randomStrings(n)
+		want := slices.Clone(input)
+		slices.Sort(want)
+		Sort(input)
+		if !slices.Equal(want, input) {
+			t.Errorf("Random slice of length %v was not sorted", n)
+		}
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	input := randomStrings(1000000)
+	data := make([]string, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(data, input)
+		Sort(data)
+	}
+}
+
+func BenchmarkHeapSort(b *testing.B) {
+	input := randomStrings(1000000)
+	data := make([]string, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(data, input)
+		maxheap.Sort(data)
+	}
+}
+
+func BenchmarkSlicesSort(b *testing.B) {
+	input := randomStrings(1000000)
+	data := make([]string, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(data, input)
+		slices.Sort(data)
+	}
+}
@@ -0,0 +1,276 @@
+// Package radixtree implements a compressed radix tree, or trie,
+// keyed by strings.
+//
+// It is an express design decision to hard-code
+// this tree just for string keys rather than for
+// the empty interface. Each key can have a value of any one
+// type, so that the tree can serve as a map as well as a set;
+// Set is a tree with no values.
+//
+// Unlike strings/set.Set, which can only tell you whether a string
+// is a member, a radix tree keeps its keys in lexicographic order
+// and shares their common prefixes, so it can find every key that
+// starts with a given prefix, and the longest key that is a prefix
+// of a given string, as routing tables and autocompletion need.
+//
+// Each edge of the tree is labelled with a string rather than a
+// single byte, and a node with only one child and no key of its own
+// is merged into its child, so the tree has at most two nodes for
+// every key.
+package radixtree
+
+import (
+	"sort"
+)
+
+// Tree holds the root of the tree, whose label is always empty.
+type Tree[V any] struct {
+	root node[V]
+	size int
+}
+
+// node holds the label on the edge into it, and its children in
+// ascending order of the first byte of their labels, which is
+// different for every child.
+type node[V any] struct {
+	label    string
+	children []*node[V]
+	hasValue bool
+	value    V
+}
+
+// child returns the index of the child whose label starts with b,
+// and whether there is one; if there is not, the index is where it
+// would go.
+func (n *node[V]) child(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label[0] >= b
+	})
+	return i, i < len(n.children) && n.children[i].label[0] == b
+}
+
+func (n *node[V]) insertChild(i int, c *node[V]) {
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+// mergeChild merges n, which must have no value and exactly one
+// child, with that child.
+func (n *node[V]) mergeChild() {
+	c := n.children[0]
+	n.label += c.label
+	n.children = c.children
+	n.hasValue = c.hasValue
+	n.value = c.value
+}
+
+func commonPrefixLength(a string, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// New returns a new empty tree.
+func New[V any]() *Tree[V] {
+	return &Tree[V]{}
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree[V]) Len() int {
+	return t.size
+}
+
+// find returns the node for key, or nil if there is no such node.
+func (t *Tree[V]) find(key string) *node[V] {
+	n := &t.root
+	for len(key) > 0 {
+		i, ok := n.child(key[0])
+		if !ok {
+			return nil
+		}
+		c := n.children[i]
+		if len(key) < len(c.label) || key[:len(c.label)] != c.label {
+			return nil
+		}
+		key = key[len(c.label):]
+		n = c
+	}
+	return n
+}
+
+// Get returns the value for key, and whether key is in the tree.
+func (t *Tree[V]) Get(key string) (V, bool) {
+	n := t.find(key)
+	if n == nil || !n.hasValue {
+		var zero V
+		return zero, false
+	}
+	return n.value, true
+}
+
+func (t *Tree[V]) Has(key string) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// Put sets the value for key, replacing any value it already had.
+func (t *Tree[V]) Put(key string, value V) {
+	n := &t.root
+	for len(key) > 0 {
+		i, ok := n.child(key[0])
+		if !ok {
+			n.insertChild(i, &node[V]{label: key, hasValue: true, value: value})
+			t.size++
+			return
+		}
+		c := n.children[i]
+		l := commonPrefixLength(key, c.label)
+		if l < len(c.label) {
+			// Split the edge where key leaves it.
+			c.label = c.label[l:]
+			mid := &node[V]{label: key[:l], children: []*node[V]{c}}
+			n.children[i] = mid
+			c = mid
+		}
+		key = key[l:]
+		n = c
+	}
+	if !n.hasValue {
+		t.size++
+	}
+	n.hasValue = true
+	n.value = value
+}
+
+// Delete deletes key from the tree. It returns false if key
+// was not in the tree.
+func (t *Tree[V]) Delete(key string) bool {
+	var parent *node[V]
+	var index int
+	n := &t.root
+	for len(key) > 0 {
+		i, ok := n.child(key[0])
+		if !ok {
+			return false
+		}
+		c := n.children[i]
+		if len(key) < len(c.label) || key[:len(c.label)] != c.label {
+			return false
+		}
+		key = key[len(c.label):]
+		parent, index, n = n, i, c
+	}
+	if !n.hasValue {
+		return false
+	}
+	var zero V
+	n.hasValue = false
+	n.value = zero
+	t.size--
+	if parent == nil {
+		// The root stays, even with no value and one child.
+		return true
+	}
+	switch len(n.children) {
+	case 0:
+		parent.children = append(parent.children[:index], parent.children[index+1:]...)
+		if parent != &t.root && !parent.hasValue && len(parent.children) == 1 {
+			parent.mergeChild()
+		}
+	case 1:
+		n.mergeChild()
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key in the tree that is a prefix
+// of s, along with its value. It returns false if no key in the tree
+// is a prefix of s.
+func (t *Tree[V]) LongestPrefix(s string) (string, V, bool) {
+	var found *node[V]
+	foundLength := 0
+	consumed := 0
+	n := &t.root
+	for {
+		if n.hasValue {
+			found, foundLength = n, consumed
+		}
+		if consumed == len(s) {
+			break
+		}
+		i, ok := n.child(s[consumed])
+		if !ok {
+			break
+		}
+		c := n.children[i]
+		rest := s[consumed:]
+		if len(rest) < len(c.label) || rest[:len(c.label)] != c.label {
+			break
+		}
+		consumed += len(c.label)
+		n = c
+	}
+	if found == nil {
+		var zero V
+		return "", zero, false
+	}
+	return s[:foundLength], found.value, true
+}
+
+// Iter iterates through every key in the tree in lexicographic
+// order and calls function f using the key and its value as arguments.
+func (t *Tree[V]) Iter(f func(key string, value V)) {
+	walk(&t.root, []byte{}, f)
+}
+
+// IterPrefix iterates through every key in the tree that starts
+// with prefix, in lexicographic order, and calls function f using
+// the key and its value as arguments.
+func (t *Tree[V]) IterPrefix(prefix string, f func(key string, value V)) {
+	n := &t.root
+	path := make([]byte, 0, len(prefix))
+	rest := prefix
+	for len(rest) > 0 {
+		i, ok := n.child(rest[0])
+		if !ok {
+			return
+		}
+		c := n.children[i]
+		l := commonPrefixLength(rest, c.label)
+		if l < len(rest) && l < len(c.label) {
+			// The prefix leaves the tree part way along the edge.
+			return
+		}
+		// Either the edge continues past the end of the prefix,
+		// in which case every key below c starts with it, or
+		// the prefix continues past the end of the edge.
+		path = append(path, c.label...)
+		rest = rest[l:]
+		n = c
+	}
+	walk(n, path, f)
+}
+
+// KeysWithPrefix returns every key in the tree that starts with
+// prefix, in lexicographic order.
+func (t *Tree[V]) KeysWithPrefix(prefix string) []string {
+	var keys []string
+	t.IterPrefix(prefix, func(key string, _ V) {
+		keys = append(keys, key)
+	})
+	return keys
+}
+
+// walk calls f on every key at or below n in lexicographic order,
+// where path is the key of n.
+func walk[V any](n *node[V], path []byte, f func(key string, value V)) {
+	if n.hasValue {
+		f(string(path), n.value)
+	}
+	for _, c := range n.children {
+		walk(c, append(path, c.label...), f)
+	}
+}
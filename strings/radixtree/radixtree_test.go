@@ -0,0 +1,144 @@
+package radixtree
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	tree := New[int]()
+	for i, k := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom"} {
+		tree.Put(k, i)
+	}
+	tree.Put("ruber", 100)
+	if tree.Len() != 8 {
+		t.Errorf("Expected 8 keys, got %v", tree.Len())
+	}
+	if v, ok := tree.Get("ruber"); !ok || v != 100 {
+		t.Errorf("Expected 100, got %v", v)
+	}
+	for _, k := range []string{"r", "ro", "roman", "rubicons", ""} {
+		if tree.Has(k) {
+			t.Errorf("Did not expect %q to be in the tree", k)
+		}
+	}
+	var tests = []struct {
+		prefix string
+		want   []string
+	}{
+		{"rom", []string{"rom", "romane", "romanus", "romulus"}},
+		{"roma", []string{"romane", "romanus"}},
+		{"rubic", []string{"rubicon", "rubicundus"}},
+		{"rubico", []string{"rubicon"}},
+		{"rubicx", nil},
+		{"x", nil},
+		{"", []string{"rom", "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"}},
+	}
+	for _, test := range tests {
+		if got := tree.KeysWithPrefix(test.prefix); !slices.Equal(test.want, got) {
+			t.Errorf("Expected keys with prefix %q to be %v, got %v", test.prefix, test.want, got)
+		}
+	}
+}
+
+func TestLongestPrefix(t *testing.T) {
+	tree := New[string]()
+	tree.Put("10.0.0.0/8", "a")
+	tree.Put("10.1.", "b")
+	tree.Put("10.1.2.", "c")
+	var tests = []struct {
+		s    string
+		want string
+		ok   bool
+	}{
+		{"10.1.2.3", "10.1.2.", true},
+		{"10.1.3.4", "10.1.", true},
+		{"10.1.", "10.1.", true},
+		{"10.1", "", false},
+		{"11.0.0.1", "", false},
+	}
+	for _, test := range tests {
+		got, _, ok := tree.LongestPrefix(test.s)
+		if got != test.want || ok != test.ok {
+			t.Errorf("Expected longest prefix of %q to be %q %v, got %q %v", test.s, test.want, test.ok, got, ok)
+		}
+	}
+	tree.Put("", "root")
+	if got, v, ok := tree.LongestPrefix("11"); !ok || got != "" || v != "root" {
+		t.Errorf("Expected the empty key to be the longest prefix, got %q %v", got, ok)
+	}
+}
+
+// TestRandom checks the tree against a map and a sorted slice,
+// using a small alphabet so that keys share many prefixes.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomKey := func() string {
+		b := make([]byte, r.Intn(6))
+		for i := range b {
+			b[i] = "abc"[r.Intn(3)]
+		}
+		return string(b)
+	}
+	tree := New[int]()
+	want := make(map[string]int)
+	for i := 0; i < 5000; i++ {
+		k := randomKey()
+		if r.Intn(3) > 0 {
+			tree.Put(k, i)
+			want[k] = i
+		} else {
+			_, ok := want[k]
+			if tree.Delete(k) != ok {
+				t.Fatalf("Expected Delete(%q) to return %v", k, ok)
+			}
+			delete(want, k)
+		}
+	}
+	if tree.Len() != len(want) {
+		t.Errorf("Expected %v keys, got %v", len(want), tree.Len())
+	}
+	var keys []string
+	for k := range want {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var got []string
+	tree.Iter(func(k string, v int) {
+		if v != want[k] {
+			t.Errorf("Expected value %v for %q, got %v", want[k], k, v)
+		}
+		got = append(got, k)
+	})
+	if !slices.Equal(keys, got) {
+		t.Errorf("Expected %v, got %v", keys, got)
+	}
+	for i := 0; i < 200; i++ {
+		prefix := randomKey()
+		var wantKeys []string
+		longest, found := "", false
+		for _, k := range keys {
+			if strings.HasPrefix(k, prefix) {
+				wantKeys = append(wantKeys, k)
+			}
+			if strings.HasPrefix(prefix, k) && len(k) >= len(longest) {
+				longest, found = k, true
+			}
+		}
+		if got := tree.KeysWithPrefix(prefix); !slices.Equal(wantKeys, got) {
+			t.Fatalf("Expected keys with prefix %q to be %v, got %v", prefix, wantKeys, got)
+		}
+		if got, _, ok := tree.LongestPrefix(prefix); got != longest || ok != found {
+			t.Fatalf("Expected longest prefix of %q to be %q, got %q", prefix, longest, got)
+		}
+	}
+	// Deleting every key should leave nothing but the root.
+	for _, k := range keys {
+		tree.Delete(k)
+	}
+	if tree.Len() != 0 || len(tree.root.children) != 0 {
+		t.Errorf("Expected an empty tree, got %v keys and %v children", tree.Len(), len(tree.root.children))
+	}
+}
@@ -0,0 +1,79 @@
+package radixtree
+
+import (
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+// Set is a radix tree with no values. It has the same
+// Has/Put/Delete/PutSlice methods as strings/set.Set, along
+// with the prefix queries of Tree.
+type Set struct {
+	t *Tree[struct{}]
+}
+
+// NewSet returns a new empty set.
+func NewSet() *Set {
+	return &Set{t: New[struct{}]()}
+}
+
+// FromSet returns a new set holding the members of s.
+func FromSet(s set.Set) *Set {
+	r := NewSet()
+	for k := range s {
+		r.Put(k)
+	}
+	return r
+}
+
+func (s *Set) Has(k string) bool {
+	return s.t.Has(k)
+}
+
+func (s *Set) Put(k string) {
+	s.t.Put(k, struct{}{})
+}
+
+func (s *Set) Delete(k string) {
+	s.t.Delete(k)
+}
+
+func (s *Set) PutSlice(ks []string) {
+	for _, k := range ks {
+		s.Put(k)
+	}
+}
+
+// Len returns the number of members of the set.
+func (s *Set) Len() int {
+	return s.t.Len()
+}
+
+// LongestPrefix returns the longest member of the set that is a
+// prefix of k. It returns false if no member is a prefix of k.
+func (s *Set) LongestPrefix(k string) (string, bool) {
+	prefix, _, ok := s.t.LongestPrefix(k)
+	return prefix, ok
+}
+
+// Iter iterates through every member of the set in lexicographic
+// order and calls function f using the member as an argument.
+func (s *Set) Iter(f func(k string)) {
+	s.t.Iter(func(k string, _ struct{}) {
+		f(k)
+	})
+}
+
+// IterPrefix iterates through every member of the set that starts
+// with prefix, in lexicographic order, and calls function f using
+// the member as an argument.
+func (s *Set) IterPrefix(prefix string, f func(k string)) {
+	s.t.IterPrefix(prefix, func(k string, _ struct{}) {
+		f(k)
+	})
+}
+
+// KeysWithPrefix returns every member of the set that starts with
+// prefix, in lexicographic order.
+func (s *Set) KeysWithPrefix(prefix string) []string {
+	return s.t.KeysWithPrefix(prefix)
+}
@@ -0,0 +1,35 @@
+package radixtree
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/strings/set"
+)
+
+func TestSet(t *testing.T) {
+	ss := set.New()
+	ss.PutSlice([]string{"car", "cart", "carton", "dog"})
+	s := FromSet(ss)
+	s.Put("do")
+	s.Delete("dog")
+	if s.Len() != 4 {
+		t.Errorf("Expected 4 members, got %v", s.Len())
+	}
+	if !s.Has("cart") || s.Has("dog") {
+		t.Error("Membership was not as expected")
+	}
+	if got := s.KeysWithPrefix("cart"); !slices.Equal([]string{"cart", "carton"}, got) {
+		t.Errorf("Expected cart carton, got %v", got)
+	}
+	if got, ok := s.LongestPrefix("cartwheel"); !ok || got != "cart" {
+		t.Errorf("Expected cart, got %v", got)
+	}
+	var got []string
+	s.Iter(func(k string) {
+		got = append(got, k)
+	})
+	if want := []string{"car", "cart", "carton", "do"}; !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,294 @@
+// Package suffixarray implements a suffix array, along with its
+// LCP array, for fast substring search in a large immutable text.
+//
+// It is an express design decision to hard-code
+// this index just for the string type rather than for
+// the empty interface.
+//
+// The suffix array of a text lists the offsets of all of its
+// suffixes in lexicographic order of the suffixes, so that every
+// occurrence of a pattern is in one contiguous run of it, which
+// binary search can find in O(m log n) time for a pattern of length
+// m. The array is built in O(n) time using SA-IS, induced sorting
+// of the suffixes from a recursively sorted sample of them.
+//
+// The LCP array holds the length of the longest common prefix of
+// each suffix and the one before it in the suffix array. It is built
+// in O(n) time using Kasai's algorithm, and answers questions about
+// repeats, such as what the longest repeated substring is.
+//
+// Everything works on bytes, so offsets are byte offsets, as for
+// the strings package.
+package suffixarray
+
+import (
+	"sort"
+)
+
+// Index holds the text along with its suffix and LCP arrays.
+type Index struct {
+	text string
+	sa   []int
+	lcp  []int
+}
+
+// New returns a new index of text. It takes O(n) time and space.
+func New(text string) *Index {
+	s := make([]int, len(text))
+	for i := 0; i < len(text); i++ {
+		s[i] = int(text[i])
+	}
+	sa := sais(s, 255)
+	return &Index{
+		text: text,
+		sa:   sa,
+		lcp:  kasai(text, sa),
+	}
+}
+
+// Text returns the text that was indexed.
+func (x *Index) Text() string {
+	return x.text
+}
+
+// SuffixArray returns the offsets of the suffixes of the text in
+// lexicographic order of the suffixes. The slice is shared with the
+// index, so it must not be changed.
+func (x *Index) SuffixArray() []int {
+	return x.sa
+}
+
+// LCP returns the LCP array, where LCP()[i] is the length of the
+// longest common prefix of the suffixes at SuffixArray()[i-1] and
+// SuffixArray()[i], and LCP()[0] is 0. The slice is shared with
+// the index, so it must not be changed.
+func (x *Index) LCP() []int {
+	return x.lcp
+}
+
+// lookup returns the half-open range of the suffix array that
+// holds the suffixes starting with pattern.
+func (x *Index) lookup(pattern string) (int, int) {
+	lo := sort.Search(len(x.sa), func(i int) bool {
+		return x.text[x.sa[i]:] >= pattern
+	})
+	hi := lo + sort.Search(len(x.sa)-lo, func(i int) bool {
+		suffix := x.text[x.sa[lo+i]:]
+		return len(suffix) < len(pattern) || suffix[:len(pattern)] != pattern
+	})
+	return lo, hi
+}
+
+// Lookup returns the offset of every occurrence of pattern in the
+// text, in ascending order. The empty pattern occurs nowhere.
+func (x *Index) Lookup(pattern string) []int {
+	if pattern == "" {
+		return nil
+	}
+	lo, hi := x.lookup(pattern)
+	if lo == hi {
+		return nil
+	}
+	offsets := make([]int, hi-lo)
+	copy(offsets, x.sa[lo:hi])
+	sort.Ints(offsets)
+	return offsets
+}
+
+// Count returns the number of occurrences of pattern in the text,
+// which may overlap. It is faster than counting what Lookup returns.
+// The empty pattern occurs nowhere.
+func (x *Index) Count(pattern string) int {
+	if pattern == "" {
+		return 0
+	}
+	lo, hi := x.lookup(pattern)
+	return hi - lo
+}
+
+// LongestRepeatedSubstring returns the longest substring that occurs
+// at least twice in the text, where the occurrences may overlap. If
+// there is more than one, it returns the lexicographically smallest.
+// It returns the empty string if no byte of the text is repeated.
+func (x *Index) LongestRepeatedSubstring() string {
+	if len(x.lcp) == 0 {
+		return ""
+	}
+	best := 0
+	for i, l := range x.lcp {
+		if l > x.lcp[best] {
+			best = i
+		}
+	}
+	return x.text[x.sa[best] : x.sa[best]+x.lcp[best]]
+}
+
+// kasai returns the LCP array of text, given its suffix array.
+func kasai(text string, sa []int) []int {
+	n := len(text)
+	rank := make([]int, n)
+	for i, offset := range sa {
+		rank[offset] = i
+	}
+	lcp := make([]int, n)
+	// The suffix at offset i+1 shares at least h-1 bytes with its
+	// predecessor if the suffix at offset i shares h bytes with its,
+	// so h never goes down by more than one at a time.
+	h := 0
+	for i := 0; i < n; i++ {
+		if rank[i] == 0 {
+			h = 0
+			continue
+		}
+		j := sa[rank[i]-1]
+		for i+h < n && j+h < n && text[i+h] == text[j+h] {
+			h++
+		}
+		lcp[rank[i]] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
+
+// sais returns the suffix array of s, every element of which
+// is in [0, upper].
+//
+// Each suffix is an S suffix if it is smaller than the one after it,
+// or an L suffix if it is larger. The leftmost S suffixes, LMS for
+// short, of each run of S suffixes are sorted first, by giving each
+// distinct LMS substring (the text from one LMS suffix to the next) a
+// name in order and recursively sorting the string of names. Placing
+// the sorted LMS suffixes at the ends of their buckets then lets one
+// pass from the left induce the order of the L suffixes, and one pass
+// from the right the order of the S suffixes.
+func sais(s []int, upper int) []int {
+	n := len(s)
+	switch n {
+	case 0:
+		return []int{}
+	case 1:
+		return []int{0}
+	case 2:
+		if s[0] < s[1] {
+			return []int{0, 1}
+		}
+		return []int{1, 0}
+	}
+	sa := make([]int, n)
+	isS := make([]bool, n)
+	for i := n - 2; i >= 0; i-- {
+		if s[i] == s[i+1] {
+			isS[i] = isS[i+1]
+		} else {
+			isS[i] = s[i] < s[i+1]
+		}
+	}
+	// startL[c] is where the bucket for c starts, which is where its
+	// L suffixes go, and startS[c] is where its S suffixes start.
+	startL := make([]int, upper+2)
+	startS := make([]int, upper+1)
+	for i := 0; i < n; i++ {
+		if !isS[i] {
+			startS[s[i]]++
+		} else {
+			startL[s[i]+1]++
+		}
+	}
+	for c := 0; c <= upper; c++ {
+		startS[c] += startL[c]
+		startL[c+1] += startS[c]
+	}
+
+	bucket := make([]int, upper+2)
+	induce := func(lms []int) {
+		for i := range sa {
+			sa[i] = -1
+		}
+		copy(bucket, startS)
+		for _, d := range lms {
+			if d != n {
+				sa[bucket[s[d]]] = d
+				bucket[s[d]]++
+			}
+		}
+		copy(bucket, startL)
+		sa[bucket[s[n-1]]] = n - 1
+		bucket[s[n-1]]++
+		for i := 0; i < n; i++ {
+			if v := sa[i]; v >= 1 && !isS[v-1] {
+				sa[bucket[s[v-1]]] = v - 1
+				bucket[s[v-1]]++
+			}
+		}
+		copy(bucket, startL)
+		for i := n - 1; i >= 0; i-- {
+			if v := sa[i]; v >= 1 && isS[v-1] {
+				bucket[s[v-1]+1]--
+				sa[bucket[s[v-1]+1]] = v - 1
+			}
+		}
+	}
+
+	// lmsIndex[i] is the index of the LMS suffix at i among all
+	// the LMS suffixes, or -1 if i is not an LMS suffix.
+	lmsIndex := make([]int, n+1)
+	for i := range lmsIndex {
+		lmsIndex[i] = -1
+	}
+	var lms []int
+	for i := 1; i < n; i++ {
+		if !isS[i-1] && isS[i] {
+			lmsIndex[i] = len(lms)
+			lms = append(lms, i)
+		}
+	}
+	m := len(lms)
+	// Sorting the LMS suffixes by only their first character
+	// sorts them correctly by their LMS substrings.
+	induce(lms)
+	if m == 0 {
+		return sa
+	}
+
+	sortedLMS := make([]int, 0, m)
+	for _, v := range sa {
+		if lmsIndex[v] != -1 {
+			sortedLMS = append(sortedLMS, v)
+		}
+	}
+	names := make([]int, m)
+	name := 0
+	names[lmsIndex[sortedLMS[0]]] = 0
+	for i := 1; i < m; i++ {
+		l, r := sortedLMS[i-1], sortedLMS[i]
+		endL, endR := n, n
+		if lmsIndex[l]+1 < m {
+			endL = lms[lmsIndex[l]+1]
+		}
+		if lmsIndex[r]+1 < m {
+			endR = lms[lmsIndex[r]+1]
+		}
+		same := endL-l == endR-r
+		if same {
+			for l < endL && s[l] == s[r] {
+				l++
+				r++
+			}
+			if l == n || s[l] != s[r] {
+				same = false
+			}
+		}
+		if !same {
+			name++
+		}
+		names[lmsIndex[sortedLMS[i]]] = name
+	}
+	namesSA := sais(names, name)
+	for i, j := range namesSA {
+		sortedLMS[i] = lms[j]
+	}
+	induce(sortedLMS)
+	return sa
+}
@@ -0,0 +1,133 @@
+package suffixarray
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestBanana(t *testing.T) {
+	x := New("banana")
+	if want := []int{5, 3, 1, 0, 4, 2}; !slices.Equal(want, x.SuffixArray()) {
+		t.Errorf("Expected suffix array %v, got %v", want, x.SuffixArray())
+	}
+	if want := []int{0, 1, 3, 0, 0, 2}; !slices.Equal(want, x.LCP()) {
+		t.Errorf("Expected LCP array %v, got %v", want, x.LCP())
+	}
+	var tests = []struct {
+		pattern string
+		want    []int
+	}{
+		{"ana", []int{1, 3}},
+		{"a", []int{1, 3, 5}},
+		{"banana", []int{0}},
+		{"bananas", nil},
+		{"nab", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		if got := x.Lookup(test.pattern); !slices.Equal(test.want, got) {
+			t.Errorf("Expected lookup of %q to be %v, got %v", test.pattern, test.want, got)
+		}
+		if got := x.Count(test.pattern); got != len(test.want) {
+			t.Errorf("Expected count of %q to be %v, got %v", test.pattern, len(test.want), got)
+		}
+	}
+	if got := x.LongestRepeatedSubstring(); got != "ana" {
+		t.Errorf("Expected ana, got %v", got)
+	}
+}
+
+func TestEdgeCases(t *testing.T) {
+	for _, text := range []string{"", "a", "ab", "ba", "aa", "aaaaaaaa", "abcdefg"} {
+		x := New(text)
+		if got := naiveSuffixArray(text); !slices.Equal(got, x.SuffixArray()) {
+			t.Errorf("Expected suffix array of %q to be %v, got %v", text, got, x.SuffixArray())
+		}
+	}
+	if got := New("abcdefg").LongestRepeatedSubstring(); got != "" {
+		t.Errorf("Expected no repeat, got %q", got)
+	}
+	if got := New("aaaa").LongestRepeatedSubstring(); got != "aaa" {
+		t.Errorf("Expected aaa, got %q", got)
+	}
+}
+
+func naiveSuffixArray(text string) []int {
+	sa := make([]int, len(text))
+	for i := range sa {
+		sa[i] = i
+	}
+	sort.Slice(sa, func(i, j int) bool {
+		return text[sa[i]:] < text[sa[j]:]
+	})
+	return sa
+}
+
+// TestRandom checks the suffix array, the LCP array and lookups
+// against naive versions, using small alphabets so that there are
+// plenty of repeats for the recursion in SA-IS to work on.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, alphabet := range []string{"ab", "abc", "acgt", "\x00\xff"} {
+		for i := 0; i < 50; i++ {
+			b := make([]byte, r.Intn(300))
+			for j := range b {
+				b[j] = alphabet[r.Intn(len(alphabet))]
+			}
+			text := string(b)
+			x := New(text)
+			sa := naiveSuffixArray(text)
+			if !slices.Equal(sa, x.SuffixArray()) {
+				t.Fatalf("Suffix array of %q was not as expected", text)
+			}
+			longest := ""
+			for j := 1; j < len(sa); j++ {
+				a, b := text[sa[j-1]:], text[sa[j]:]
+				l := 0
+				for l < len(a) && l < len(b) && a[l] == b[l] {
+					l++
+				}
+				if x.LCP()[j] != l {
+					t.Fatalf("Expected LCP %v of %q to be %v, got %v", j, text, l, x.LCP()[j])
+				}
+				if l > len(longest) {
+					longest = a[:l]
+				}
+			}
+			if got := x.LongestRepeatedSubstring(); got != longest {
+				t.Fatalf("Expected longest repeat of %q to be %q, got %q", text, longest, got)
+			}
+			for j := 0; j < 10; j++ {
+				p := make([]byte, 1+r.Intn(4))
+				for k := range p {
+					p[k] = alphabet[r.Intn(len(alphabet))]
+				}
+				pattern := string(p)
+				var want []int
+				for k := 0; k < len(text); k++ {
+					if strings.HasPrefix(text[k:], pattern) {
+						want = append(want, k)
+					}
+				}
+				if got := x.Lookup(pattern); !slices.Equal(want, got) {
+					t.Fatalf("Expected lookup of %q in %q to be %v, got %v", pattern, text, want, got)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	text := make([]byte, 1<<20)
+	for i := range text {
+		text[i] = "acgt"[r.Intn(4)]
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(string(text))
+	}
+}
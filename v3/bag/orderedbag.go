@@ -0,0 +1,197 @@
+package bag
+
+import "iter"
+
+// OrderedBag is a bag that remembers the order in which elements
+// were first put into it, so that iterating over it is repeatable,
+// unlike iterating over Bag.
+//
+// It is a map from each element to a node in a doubly-linked list
+// that also holds the element's count, so Put, Delete, Has,
+// MoveToFront and MoveToBack are all O(1).
+type OrderedBag[T comparable] struct {
+	nodes map[T]*orderedNode[T]
+	// root is a sentinel: root.next is the first node, and
+	// root.prev is the last node.
+	root orderedNode[T]
+	// iterating is the number of iterations over the bag that
+	// are under way.
+	iterating int
+}
+
+type orderedNode[T comparable] struct {
+	elem  T
+	count int
+	prev  *orderedNode[T]
+	next  *orderedNode[T]
+}
+
+func NewOrdered[T comparable]() *OrderedBag[T] {
+	b := &OrderedBag[T]{
+		nodes: make(map[T]*orderedNode[T]),
+	}
+	b.root.next = &b.root
+	b.root.prev = &b.root
+	return b
+}
+
+// Has can tell you if the bag contains at least one of elem.
+func (b *OrderedBag[T]) Has(elem T) bool {
+	_, ok := b.nodes[elem]
+	return ok
+}
+
+// Count tells you how many copies of elem are in the bag.
+func (b *OrderedBag[T]) Count(elem T) int {
+	n, ok := b.nodes[elem]
+	if !ok {
+		return 0
+	}
+	return n.count
+}
+
+// Put puts an element in the bag. The first copy of an element
+// goes to the back of the bag; later copies join it there.
+func (b *OrderedBag[T]) Put(elem T) {
+	if n, ok := b.nodes[elem]; ok {
+		n.count++
+		return
+	}
+	n := &orderedNode[T]{elem: elem, count: 1}
+	b.insertAfter(n, b.root.prev)
+	b.nodes[elem] = n
+}
+
+func (b *OrderedBag[T]) Delete(elem T) {
+	n, ok := b.nodes[elem]
+	if !ok {
+		// Element does not exist; do nothing.
+		return
+	}
+	// Element exists; decrement.
+	n.count--
+	// If this was the last copy of this element, nuke it from the
+	// backing map and list.
+	if n.count == 0 {
+		b.unlink(n)
+		delete(b.nodes, elem)
+	}
+}
+
+func (b *OrderedBag[T]) PutSlice(elements []T) {
+	for _, elem := range elements {
+		b.Put(elem)
+	}
+}
+
+// Len returns the number of distinct elements in the bag.
+func (b *OrderedBag[T]) Len() int {
+	return len(b.nodes)
+}
+
+// MoveToFront moves all copies of an element to the front of the bag.
+// It returns false if the element is not in the bag.
+func (b *OrderedBag[T]) MoveToFront(elem T) bool {
+	n, ok := b.nodes[elem]
+	if !ok {
+		return false
+	}
+	b.move(n, true)
+	return true
+}
+
+// MoveToBack moves all copies of an element to the back of the bag.
+// It returns false if the element is not in the bag.
+func (b *OrderedBag[T]) MoveToBack(elem T) bool {
+	n, ok := b.nodes[elem]
+	if !ok {
+		return false
+	}
+	b.move(n, false)
+	return true
+}
+
+// Iter iterates through every element of the bag in order and calls
+// function f using the element as an argument for T, once per copy.
+// As with ranging over a map, f may delete elements from the bag;
+// an element that has been deleted, down to its last copy, before
+// it is reached, or while it is being visited, is not visited
+// again.
+// f may also move elements with MoveToFront and MoveToBack. A moved
+// element is visited at its new place if the iteration has yet to
+// reach that place, so may be visited twice or not at all, but
+// every other element is still visited exactly once.
+func (b *OrderedBag[T]) Iter(f func(elem T)) {
+	b.iterating++
+	defer func() { b.iterating-- }()
+	for n := b.root.next; n != &b.root; {
+		next := n.next
+		for i := n.count; i > 0 && n.count > 0; i-- {
+			f(n.elem)
+		}
+		n = b.live(next)
+	}
+}
+
+// All returns an iterator over every distinct element of the bag
+// in order, along with its count. Elements may be deleted from the
+// bag or moved during iteration, as for Iter.
+func (b *OrderedBag[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		b.iterating++
+		defer func() { b.iterating-- }()
+		for n := b.root.next; n != &b.root; {
+			next := n.next
+			if !yield(n.elem, n.count) {
+				return
+			}
+			n = b.live(next)
+		}
+	}
+}
+
+// move moves n to the front of the list, or to the back. While the
+// bag is being iterated over, n is unlinked as if deleted, so that
+// an iteration about to step from n still finds its way on, and a
+// new node takes its place; this way the iteration neither skips
+// nor repeats the elements that the move jumps over.
+func (b *OrderedBag[T]) move(n *orderedNode[T], toFront bool) {
+	b.unlink(n)
+	if b.iterating > 0 {
+		moved := *n
+		n = &moved
+		b.nodes[n.elem] = n
+	}
+	if toFront {
+		b.insertAfter(n, &b.root)
+	} else {
+		b.insertAfter(n, b.root.prev)
+	}
+}
+
+func (b *OrderedBag[T]) insertAfter(n, at *orderedNode[T]) {
+	n.prev = at
+	n.next = at.next
+	at.next.prev = n
+	at.next = n
+}
+
+// unlink takes n out of the list. It leaves n.next alone, so that an
+// iteration that is about to step from n to n.next can still find
+// its way back into the list; n.prev being nil marks n as unlinked.
+func (b *OrderedBag[T]) unlink(n *orderedNode[T]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev = nil
+}
+
+// live returns n, or if n has been unlinked, the first node still in
+// the list that followed it. Every unlinked node points to a node
+// that was still in the list when it was unlinked, so this always
+// ends at a node in the list or at root.
+func (b *OrderedBag[T]) live(n *orderedNode[T]) *orderedNode[T] {
+	for n != &b.root && n.prev == nil {
+		n = n.next
+	}
+	return n
+}
@@ -0,0 +1,118 @@
+package bag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrdered(t *testing.T) {
+	b := NewOrdered[string]()
+	b.PutSlice([]string{"c", "a", "c", "b"})
+	b.Put("a")
+	got := []string{}
+	b.Iter(func(elem string) {
+		got = append(got, elem)
+	})
+	want := []string{"c", "c", "a", "a", "b"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+
+	if b.Count("c") != 2 {
+		t.Errorf("should have 2 copies of c, got %v", b.Count("c"))
+	}
+	b.Delete("c")
+	if !b.Has("c") {
+		t.Errorf("should have c after one deletion")
+	}
+	b.Delete("c")
+	if b.Has("c") {
+		t.Errorf("should NOT have c after two deletions")
+	}
+
+	b.Put("c")
+	b.MoveToFront("b")
+	b.MoveToBack("a")
+	gotElems := []string{}
+	gotCounts := []int{}
+	for elem, count := range b.All() {
+		gotElems = append(gotElems, elem)
+		gotCounts = append(gotCounts, count)
+	}
+	if want := []string{"b", "c", "a"}; !reflect.DeepEqual(want, gotElems) {
+		t.Errorf("expected want %#v to equal got %#v", want, gotElems)
+	}
+	if want := []int{1, 1, 2}; !reflect.DeepEqual(want, gotCounts) {
+		t.Errorf("expected want %#v to equal got %#v", want, gotCounts)
+	}
+	if b.MoveToFront("z") {
+		t.Errorf("should not be able to move an element that is not in the bag")
+	}
+}
+
+func TestOrderedDeleteDuringIteration(t *testing.T) {
+	b := NewOrdered[string]()
+	b.PutSlice([]string{"a", "b", "b", "c", "d"})
+	gotElems := []string{}
+	for elem, count := range b.All() {
+		gotElems = append(gotElems, elem)
+		for i := 0; i < count; i++ {
+			b.Delete(elem)
+		}
+		if elem == "b" {
+			b.Delete("c")
+		}
+	}
+	if want := []string{"a", "b", "d"}; !reflect.DeepEqual(want, gotElems) {
+		t.Errorf("expected want %#v to equal got %#v", want, gotElems)
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected length 0, got %v", b.Len())
+	}
+
+	// Deleting one copy each time still visits every copy, but
+	// deleting every copy of an element stops its visits.
+	b.PutSlice([]string{"a", "a", "a", "b", "b", "c"})
+	got := []string{}
+	b.Iter(func(elem string) {
+		got = append(got, elem)
+		b.Delete(elem)
+		if elem == "b" {
+			b.Delete("b")
+		}
+	})
+	if want := []string{"a", "a", "a", "b", "c"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+}
+
+func TestOrderedMoveDuringIteration(t *testing.T) {
+	b := NewOrdered[string]()
+	b.PutSlice([]string{"a", "b", "b", "c", "d"})
+	gotElems := []string{}
+	gotCounts := []int{}
+	for elem, count := range b.All() {
+		gotElems = append(gotElems, elem)
+		gotCounts = append(gotCounts, count)
+		if elem == "a" {
+			b.MoveToBack("b")
+			b.MoveToFront("d")
+		}
+	}
+	if want := []string{"a", "c", "b"}; !reflect.DeepEqual(want, gotElems) {
+		t.Errorf("expected want %#v to equal got %#v", want, gotElems)
+	}
+	if want := []int{1, 1, 2}; !reflect.DeepEqual(want, gotCounts) {
+		t.Errorf("expected want %#v to equal got %#v", want, gotCounts)
+	}
+	got := []string{}
+	b.Iter(func(elem string) {
+		got = append(got, elem)
+	})
+	if want := []string{"d", "a", "c", "b", "b"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+	if b.Count("b") != 2 {
+		t.Errorf("should have 2 copies of b, got %v", b.Count("b"))
+	}
+}
@@ -0,0 +1,499 @@
+// Package btree implements an ordered map and an ordered set
+// as B-trees.
+//
+// Unlike a Go map, or set.Set, a BTreeMap keeps its keys in
+// order, so besides Get, Put and Delete, it can find the smallest
+// and largest keys, the keys closest to a given key, and iterate
+// over the keys in a range in order. Every operation takes
+// O(log n) time.
+//
+// A B-tree of degree t keeps between t-1 and 2t-1 keys in every
+// node but the root, and every leaf at the same depth. A larger
+// degree makes the tree shallower, and makes each node a longer
+// run of memory to search, which suits the CPU cache better than
+// a binary tree does.
+package btree
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// DefaultDegree is the degree of the tree when constructed
+// using NewMap() instead of NewMapWithDegree().
+const DefaultDegree = 16
+
+type InvalidDegreeError struct {
+	msg string
+}
+
+func (e *InvalidDegreeError) Error() string {
+	return e.msg
+}
+
+type UnsortedInputError struct {
+	msg string
+}
+
+func (e *UnsortedInputError) Error() string {
+	return e.msg
+}
+
+// BTreeMap holds the root of the tree and its degree.
+type BTreeMap[K cmp.Ordered, V any] struct {
+	root   *node[K, V]
+	degree int
+	size   int
+}
+
+type entry[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+}
+
+// node holds the entries of a node in ascending order of key. An
+// interior node has one more child than it has entries, and every
+// key in children[i] lies between entries[i-1] and entries[i].
+// A leaf has no children.
+type node[K cmp.Ordered, V any] struct {
+	entries  []entry[K, V]
+	children []*node[K, V]
+}
+
+func (n *node[K, V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// search returns the index of the entry for key in n, and whether
+// it is there; if it is not, the index is that of the child that
+// key would be in.
+func (n *node[K, V]) search(key K) (int, bool) {
+	return slices.BinarySearchFunc(n.entries, key, func(e entry[K, V], key K) int {
+		return cmp.Compare(e.key, key)
+	})
+}
+
+// NewMap returns a new empty map of the default degree.
+func NewMap[K cmp.Ordered, V any]() (*BTreeMap[K, V], error) {
+	return NewMapWithDegree[K, V](DefaultDegree)
+}
+
+// NewMapWithDegree returns a new empty map of the requested degree,
+// which must be at least 2.
+func NewMapWithDegree[K cmp.Ordered, V any](degree int) (*BTreeMap[K, V], error) {
+	if degree < 2 {
+		return nil, &InvalidDegreeError{
+			msg: fmt.Sprintf("requested degree %d is less than 2", degree),
+		}
+	}
+	return &BTreeMap[K, V]{
+		root:   &node[K, V]{},
+		degree: degree,
+	}, nil
+}
+
+func (m *BTreeMap[K, V]) maxEntries() int {
+	return 2*m.degree - 1
+}
+
+// Len returns the number of keys in the map.
+func (m *BTreeMap[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value for key, and whether key is in the map.
+func (m *BTreeMap[K, V]) Get(key K) (V, bool) {
+	n := m.root
+	for {
+		i, found := n.search(key)
+		if found {
+			return n.entries[i].value, true
+		}
+		if n.leaf() {
+			var zero V
+			return zero, false
+		}
+		n = n.children[i]
+	}
+}
+
+// Has tells you whether key is in the map.
+func (m *BTreeMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Put sets the value for key, replacing any value it already had.
+func (m *BTreeMap[K, V]) Put(key K, value V) {
+	if len(m.root.entries) == m.maxEntries() {
+		// Split a full root first, which is the only way
+		// the tree gets taller.
+		old := m.root
+		m.root = &node[K, V]{children: []*node[K, V]{old}}
+		m.splitChild(m.root, 0)
+	}
+	n := m.root
+	for {
+		i, found := n.search(key)
+		if found {
+			n.entries[i].value = value
+			return
+		}
+		if n.leaf() {
+			n.entries = slices.Insert(n.entries, i, entry[K, V]{key, value})
+			m.size++
+			return
+		}
+		// Split a full child before going into it, so that
+		// there is always room to insert into a leaf, or to
+		// take the middle entry of a split child.
+		if len(n.children[i].entries) == m.maxEntries() {
+			m.splitChild(n, i)
+			switch c := cmp.Compare(key, n.entries[i].key); {
+			case c == 0:
+				n.entries[i].value = value
+				return
+			case c > 0:
+				i++
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// splitChild splits the full child i of n in two, moving its
+// middle entry up into n.
+func (m *BTreeMap[K, V]) splitChild(n *node[K, V], i int) {
+	child := n.children[i]
+	t := m.degree
+	right := &node[K, V]{
+		entries: slices.Clone(child.entries[t:]),
+	}
+	if !child.leaf() {
+		right.children = slices.Clone(child.children[t:])
+		clear(child.children[t:])
+		child.children = child.children[:t]
+	}
+	middle := child.entries[t-1]
+	clear(child.entries[t-1:])
+	child.entries = child.entries[:t-1]
+	n.entries = slices.Insert(n.entries, i, middle)
+	n.children = slices.Insert(n.children, i+1, right)
+}
+
+// Delete deletes key from the map. It returns false if key
+// was not in the map.
+func (m *BTreeMap[K, V]) Delete(key K) bool {
+	deleted := m.delete(m.root, key)
+	if len(m.root.entries) == 0 && !m.root.leaf() {
+		// The root's last entry was merged into its only
+		// child, which is the only way the tree gets shorter.
+		m.root = m.root.children[0]
+	}
+	if deleted {
+		m.size--
+	}
+	return deleted
+}
+
+// delete deletes key from the subtree rooted at n, which, unless it
+// is the root, has at least degree entries, so that taking one entry
+// out of it leaves it with enough.
+func (m *BTreeMap[K, V]) delete(n *node[K, V], key K) bool {
+	t := m.degree
+	i, found := n.search(key)
+	if n.leaf() {
+		if found {
+			n.entries = slices.Delete(n.entries, i, i+1)
+		}
+		return found
+	}
+	if found {
+		switch {
+		case len(n.children[i].entries) >= t:
+			// Replace the entry with its predecessor.
+			pred := n.children[i]
+			for !pred.leaf() {
+				pred = pred.children[len(pred.children)-1]
+			}
+			n.entries[i] = pred.entries[len(pred.entries)-1]
+			return m.delete(n.children[i], n.entries[i].key)
+		case len(n.children[i+1].entries) >= t:
+			// Replace the entry with its successor.
+			succ := n.children[i+1]
+			for !succ.leaf() {
+				succ = succ.children[0]
+			}
+			n.entries[i] = succ.entries[0]
+			return m.delete(n.children[i+1], n.entries[i].key)
+		default:
+			m.merge(n, i)
+			return m.delete(n.children[i], key)
+		}
+	}
+	if len(n.children[i].entries) < t {
+		i = m.fill(n, i)
+	}
+	return m.delete(n.children[i], key)
+}
+
+// fill gives child i of n, which has degree-1 entries, another
+// entry, either from a sibling or by merging it with a sibling.
+// It returns the new index of the child.
+func (m *BTreeMap[K, V]) fill(n *node[K, V], i int) int {
+	t := m.degree
+	child := n.children[i]
+	switch {
+	case i > 0 && len(n.children[i-1].entries) >= t:
+		// Rotate an entry from the left sibling through n.
+		left := n.children[i-1]
+		child.entries = slices.Insert(child.entries, 0, n.entries[i-1])
+		n.entries[i-1] = left.entries[len(left.entries)-1]
+		left.entries = left.entries[:len(left.entries)-1]
+		if !left.leaf() {
+			child.children = slices.Insert(child.children, 0, left.children[len(left.children)-1])
+			left.children[len(left.children)-1] = nil
+			left.children = left.children[:len(left.children)-1]
+		}
+		return i
+	case i < len(n.entries) && len(n.children[i+1].entries) >= t:
+		// Rotate an entry from the right sibling through n.
+		right := n.children[i+1]
+		child.entries = append(child.entries, n.entries[i])
+		n.entries[i] = right.entries[0]
+		right.entries = slices.Delete(right.entries, 0, 1)
+		if !right.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+		return i
+	case i < len(n.entries):
+		m.merge(n, i)
+		return i
+	default:
+		m.merge(n, i-1)
+		return i - 1
+	}
+}
+
+// merge merges child i+1 of n, and entry i of n, into child i.
+func (m *BTreeMap[K, V]) merge(n *node[K, V], i int) {
+	child, right := n.children[i], n.children[i+1]
+	child.entries = append(child.entries, n.entries[i])
+	child.entries = append(child.entries, right.entries...)
+	child.children = append(child.children, right.children...)
+	n.entries = slices.Delete(n.entries, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+}
+
+// Min returns the smallest key in the map and its value.
+// It returns false if the map is empty.
+func (m *BTreeMap[K, V]) Min() (K, V, bool) {
+	if m.size == 0 {
+		return unpack[K, V](nil)
+	}
+	n := m.root
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return unpack(&n.entries[0])
+}
+
+// Max returns the largest key in the map and its value.
+// It returns false if the map is empty.
+func (m *BTreeMap[K, V]) Max() (K, V, bool) {
+	if m.size == 0 {
+		return unpack[K, V](nil)
+	}
+	n := m.root
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return unpack(&n.entries[len(n.entries)-1])
+}
+
+// Floor returns the largest key in the map that is <= key, and its
+// value. It returns false if there is no such key.
+func (m *BTreeMap[K, V]) Floor(key K) (K, V, bool) {
+	var found *entry[K, V]
+	n := m.root
+	for {
+		i, ok := n.search(key)
+		if ok {
+			return unpack(&n.entries[i])
+		}
+		if i > 0 {
+			found = &n.entries[i-1]
+		}
+		if n.leaf() {
+			return unpack(found)
+		}
+		n = n.children[i]
+	}
+}
+
+// Ceiling returns the smallest key in the map that is >= key, and
+// its value. It returns false if there is no such key.
+func (m *BTreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var found *entry[K, V]
+	n := m.root
+	for {
+		i, ok := n.search(key)
+		if ok {
+			return unpack(&n.entries[i])
+		}
+		if i < len(n.entries) {
+			found = &n.entries[i]
+		}
+		if n.leaf() {
+			return unpack(found)
+		}
+		n = n.children[i]
+	}
+}
+
+func unpack[K cmp.Ordered, V any](e *entry[K, V]) (K, V, bool) {
+	if e == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+	return e.key, e.value, true
+}
+
+// Ascend returns an iterator over every key in the map and its
+// value, in ascending order of key.
+func (m *BTreeMap[K, V]) Ascend() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, nil, nil, yield)
+	}
+}
+
+// Descend returns an iterator over every key in the map and its
+// value, in descending order of key.
+func (m *BTreeMap[K, V]) Descend() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		descend(m.root, yield)
+	}
+}
+
+// Range returns an iterator over every key in the map that is in
+// the half-open range [lo, hi) and its value, in ascending order
+// of key.
+func (m *BTreeMap[K, V]) Range(lo K, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, &lo, &hi, yield)
+	}
+}
+
+// ascend yields the entries of the subtree rooted at n whose keys
+// are >= lo and < hi in order, where a nil bound is no bound. It
+// returns false if yield asked it to stop.
+func ascend[K cmp.Ordered, V any](n *node[K, V], lo *K, hi *K, yield func(K, V) bool) bool {
+	start := 0
+	if lo != nil {
+		start, _ = n.search(*lo)
+	}
+	for i := start; i <= len(n.entries); i++ {
+		if !n.leaf() && !ascend(n.children[i], lo, hi, yield) {
+			return false
+		}
+		if i == len(n.entries) {
+			break
+		}
+		e := n.entries[i]
+		if hi != nil && e.key >= *hi {
+			return false
+		}
+		if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func descend[K cmp.Ordered, V any](n *node[K, V], yield func(K, V) bool) bool {
+	for i := len(n.entries); i >= 0; i-- {
+		if !n.leaf() && !descend(n.children[i], yield) {
+			return false
+		}
+		if i == 0 {
+			break
+		}
+		e := n.entries[i-1]
+		if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// FromSorted returns a new map of the requested degree holding the
+// keys and values given, where values[i] is the value for keys[i].
+// The keys must be in strictly ascending order. It builds the tree
+// bottom up in O(n) time, rather than the O(n log n) time that
+// putting the keys in one at a time would take.
+func FromSorted[K cmp.Ordered, V any](degree int, keys []K, values []V) (*BTreeMap[K, V], error) {
+	m, err := NewMapWithDegree[K, V](degree)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != len(values) {
+		return nil, &UnsortedInputError{
+			msg: fmt.Sprintf("%d keys but %d values", len(keys), len(values)),
+		}
+	}
+	entries := make([]entry[K, V], len(keys))
+	for i, key := range keys {
+		if i > 0 && key <= keys[i-1] {
+			return nil, &UnsortedInputError{
+				msg: fmt.Sprintf("key %v at index %d is not greater than %v", key, i, keys[i-1]),
+			}
+		}
+		entries[i] = entry[K, V]{key, values[i]}
+	}
+	nodes, separators := m.buildLevel(entries, nil)
+	for len(nodes) > 1 {
+		nodes, separators = m.buildLevel(separators, nodes)
+	}
+	m.root = nodes[0]
+	m.size = len(keys)
+	return m, nil
+}
+
+// buildLevel splits entries, which are in order, between as few
+// nodes as it can, each with between degree-1 and 2*degree-1
+// entries, and returns them along with the entries that separate
+// them. If children is not nil, it holds one more node than there
+// are entries, and the nodes are given them in order.
+func (m *BTreeMap[K, V]) buildLevel(entries []entry[K, V], children []*node[K, V]) ([]*node[K, V], []entry[K, V]) {
+	n := len(entries)
+	if n <= m.maxEntries() {
+		return []*node[K, V]{{entries: entries, children: children}}, nil
+	}
+	// Every node but the last takes up to 2*degree-1 entries,
+	// and one more to separate it from the next.
+	count := (n + 2*m.degree) / (2 * m.degree)
+	inNodes := n - (count - 1)
+	nodes := make([]*node[K, V], count)
+	separators := make([]entry[K, V], 0, count-1)
+	for i := range nodes {
+		size := inNodes / count
+		if i < inNodes%count {
+			size++
+		}
+		nodes[i] = &node[K, V]{entries: slices.Clone(entries[:size])}
+		entries = entries[size:]
+		if children != nil {
+			nodes[i].children = slices.Clone(children[:size+1])
+			children = children[size+1:]
+		}
+		if i < count-1 {
+			separators = append(separators, entries[0])
+			entries = entries[1:]
+		}
+	}
+	return nodes, separators
+}
@@ -0,0 +1,227 @@
+package btree
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkInvariants checks that every node but the root has between
+// degree-1 and 2*degree-1 entries, that every leaf is at the same
+// depth, and that the keys are in order.
+func checkInvariants[K int | string, V any](t *testing.T, m *BTreeMap[K, V]) {
+	t.Helper()
+	leafDepth := -1
+	count := 0
+	var walk func(n *node[K, V], depth int, lo *K, hi *K)
+	walk = func(n *node[K, V], depth int, lo *K, hi *K) {
+		if n != m.root && (len(n.entries) < m.degree-1 || len(n.entries) > m.maxEntries()) {
+			t.Fatalf("Node at depth %v has %v entries", depth, len(n.entries))
+		}
+		for i, e := range n.entries {
+			if i > 0 && e.key <= n.entries[i-1].key ||
+				lo != nil && e.key <= *lo || hi != nil && e.key >= *hi {
+				t.Fatalf("Key %v at depth %v is out of order", e.key, depth)
+			}
+		}
+		count += len(n.entries)
+		if n.leaf() {
+			if leafDepth >= 0 && depth != leafDepth {
+				t.Fatalf("Leaves at depths %v and %v", leafDepth, depth)
+			}
+			leafDepth = depth
+			return
+		}
+		if len(n.children) != len(n.entries)+1 {
+			t.Fatalf("Node at depth %v has %v entries and %v children", depth, len(n.entries), len(n.children))
+		}
+		for i, c := range n.children {
+			childLo, childHi := lo, hi
+			if i > 0 {
+				childLo = &n.entries[i-1].key
+			}
+			if i < len(n.entries) {
+				childHi = &n.entries[i].key
+			}
+			walk(c, depth+1, childLo, childHi)
+		}
+	}
+	walk(m.root, 0, nil, nil)
+	if count != m.Len() {
+		t.Fatalf("Expected %v keys, found %v", m.Len(), count)
+	}
+}
+
+func TestNewMap(t *testing.T) {
+	if _, err := NewMapWithDegree[int, int](1); err == nil {
+		t.Error("Expected an error for degree 1")
+	}
+	m, err := NewMap[int, string]()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, _, ok := m.Min(); ok {
+		t.Error("Expected no min in an empty map")
+	}
+	if _, _, ok := m.Floor(5); ok {
+		t.Error("Expected no floor in an empty map")
+	}
+	if m.Delete(5) {
+		t.Error("Did not expect to delete from an empty map")
+	}
+}
+
+func TestMap(t *testing.T) {
+	m, _ := NewMapWithDegree[int, string](2)
+	for _, k := range []int{50, 10, 40, 20, 30, 60, 70} {
+		m.Put(k, "v")
+	}
+	m.Put(40, "forty")
+	if v, ok := m.Get(40); !ok || v != "forty" {
+		t.Errorf("Expected forty, got %v", v)
+	}
+	if _, ok := m.Get(45); ok {
+		t.Error("Did not expect 45 to be in the map")
+	}
+	checkInvariants(t, m)
+	var tests = []struct {
+		key     int
+		floor   int
+		ceiling int
+	}{
+		{5, -1, 10},
+		{10, 10, 10},
+		{35, 30, 40},
+		{70, 70, 70},
+		{75, 70, -1},
+	}
+	for _, test := range tests {
+		if got, _, ok := m.Floor(test.key); ok != (test.floor >= 0) || ok && got != test.floor {
+			t.Errorf("Expected floor of %v to be %v, got %v %v", test.key, test.floor, got, ok)
+		}
+		if got, _, ok := m.Ceiling(test.key); ok != (test.ceiling >= 0) || ok && got != test.ceiling {
+			t.Errorf("Expected ceiling of %v to be %v, got %v %v", test.key, test.ceiling, got, ok)
+		}
+	}
+	if min, _, _ := m.Min(); min != 10 {
+		t.Errorf("Expected min 10, got %v", min)
+	}
+	if max, _, _ := m.Max(); max != 70 {
+		t.Errorf("Expected max 70, got %v", max)
+	}
+	var got []int
+	for k := range m.Range(20, 60) {
+		got = append(got, k)
+	}
+	if want := []int{20, 30, 40, 50}; !slices.Equal(want, got) {
+		t.Errorf("Expected range %v, got %v", want, got)
+	}
+	got = nil
+	for k := range m.Descend() {
+		got = append(got, k)
+		if k == 40 {
+			break
+		}
+	}
+	if want := []int{70, 60, 50, 40}; !slices.Equal(want, got) {
+		t.Errorf("Expected descent %v, got %v", want, got)
+	}
+}
+
+// TestRandom checks every operation against a Go map, at several
+// degrees, checking the shape of the tree as it goes.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, degree := range []int{2, 3, 4, 16} {
+		m, _ := NewMapWithDegree[int, int](degree)
+		want := make(map[int]int)
+		for i := 0; i < 4000; i++ {
+			k := r.Intn(500)
+			if r.Intn(3) > 0 {
+				m.Put(k, i)
+				want[k] = i
+			} else {
+				_, ok := want[k]
+				if m.Delete(k) != ok {
+					t.Fatalf("degree %v: expected Delete(%v) to return %v", degree, k, ok)
+				}
+				delete(want, k)
+			}
+			if i%100 == 0 {
+				checkInvariants(t, m)
+			}
+		}
+		checkInvariants(t, m)
+		keys := slices.Sorted(maps.Keys(want))
+		var got []int
+		for k, v := range m.Ascend() {
+			if v != want[k] {
+				t.Errorf("degree %v: expected value %v for %v, got %v", degree, want[k], k, v)
+			}
+			got = append(got, k)
+		}
+		if !slices.Equal(keys, got) {
+			t.Errorf("degree %v: keys were not as expected", degree)
+		}
+		for i := 0; i < 200; i++ {
+			lo := r.Intn(520) - 10
+			hi := lo + r.Intn(100)
+			var wantRange []int
+			for _, k := range keys {
+				if k >= lo && k < hi {
+					wantRange = append(wantRange, k)
+				}
+			}
+			var gotRange []int
+			for k := range m.Range(lo, hi) {
+				gotRange = append(gotRange, k)
+			}
+			if !slices.Equal(wantRange, gotRange) {
+				t.Fatalf("degree %v: expected range [%v, %v) to be %v, got %v", degree, lo, hi, wantRange, gotRange)
+			}
+		}
+		for len(keys) > 0 {
+			k := keys[len(keys)-1]
+			keys = keys[:len(keys)-1]
+			m.Delete(k)
+		}
+		checkInvariants(t, m)
+	}
+}
+
+func TestFromSorted(t *testing.T) {
+	if _, err := FromSorted(2, []int{1, 3, 2}, []int{0, 0, 0}); err == nil {
+		t.Error("Expected an error for unsorted keys")
+	}
+	if _, err := FromSorted(2, []int{1, 2}, []int{0}); err == nil {
+		t.Error("Expected an error for mismatched keys and values")
+	}
+	for _, degree := range []int{2, 3, 5} {
+		for n := 0; n < 200; n++ {
+			keys := make([]int, n)
+			for i := range keys {
+				keys[i] = 2 * i
+			}
+			m, err := FromSorted(degree, keys, keys)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			checkInvariants(t, m)
+			var got []int
+			for k, v := range m.Ascend() {
+				if k != v {
+					t.Fatalf("Expected value %v for %v, got %v", k, k, v)
+				}
+				got = append(got, k)
+			}
+			if !slices.Equal(keys, got) {
+				t.Fatalf("degree %v n %v: keys were not as expected", degree, n)
+			}
+			// A bulk-loaded tree must still take puts and deletes.
+			m.Put(1, 1)
+			m.Delete(0)
+			checkInvariants(t, m)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package btree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// BTreeSet is an ordered set. It has the same Has/Put/Delete/PutSlice
+// methods as set.Set, along with the ordered access of BTreeMap.
+type BTreeSet[K cmp.Ordered] struct {
+	m *BTreeMap[K, struct{}]
+}
+
+// NewSet returns a new empty set of the default degree.
+func NewSet[K cmp.Ordered]() (*BTreeSet[K], error) {
+	return NewSetWithDegree[K](DefaultDegree)
+}
+
+// NewSetWithDegree returns a new empty set of the requested degree,
+// which must be at least 2.
+func NewSetWithDegree[K cmp.Ordered](degree int) (*BTreeSet[K], error) {
+	m, err := NewMapWithDegree[K, struct{}](degree)
+	if err != nil {
+		return nil, err
+	}
+	return &BTreeSet[K]{m: m}, nil
+}
+
+// SetFromSorted returns a new set of the requested degree holding
+// keys, which must be in strictly ascending order. Like FromSorted,
+// it takes O(n) time.
+func SetFromSorted[K cmp.Ordered](degree int, keys []K) (*BTreeSet[K], error) {
+	m, err := FromSorted(degree, keys, make([]struct{}, len(keys)))
+	if err != nil {
+		return nil, err
+	}
+	return &BTreeSet[K]{m: m}, nil
+}
+
+func (s *BTreeSet[K]) Has(elem K) bool {
+	return s.m.Has(elem)
+}
+
+func (s *BTreeSet[K]) Put(elem K) {
+	s.m.Put(elem, struct{}{})
+}
+
+// Delete deletes elem from the set. It returns false if elem
+// was not in the set.
+func (s *BTreeSet[K]) Delete(elem K) bool {
+	return s.m.Delete(elem)
+}
+
+func (s *BTreeSet[K]) PutSlice(elements []K) {
+	for _, elem := range elements {
+		s.Put(elem)
+	}
+}
+
+// Len returns the number of elements in the set.
+func (s *BTreeSet[K]) Len() int {
+	return s.m.Len()
+}
+
+// Min returns the smallest element of the set. It returns
+// false if the set is empty.
+func (s *BTreeSet[K]) Min() (K, bool) {
+	elem, _, ok := s.m.Min()
+	return elem, ok
+}
+
+// Max returns the largest element of the set. It returns
+// false if the set is empty.
+func (s *BTreeSet[K]) Max() (K, bool) {
+	elem, _, ok := s.m.Max()
+	return elem, ok
+}
+
+// Floor returns the largest element of the set that is <= elem.
+// It returns false if there is no such element.
+func (s *BTreeSet[K]) Floor(elem K) (K, bool) {
+	floor, _, ok := s.m.Floor(elem)
+	return floor, ok
+}
+
+// Ceiling returns the smallest element of the set that is >= elem.
+// It returns false if there is no such element.
+func (s *BTreeSet[K]) Ceiling(elem K) (K, bool) {
+	ceiling, _, ok := s.m.Ceiling(elem)
+	return ceiling, ok
+}
+
+// Iter iterates through every element of the set in ascending
+// order and calls function f using the element as an argument.
+func (s *BTreeSet[K]) Iter(f func(elem K)) {
+	for elem := range s.Ascend() {
+		f(elem)
+	}
+}
+
+// Ascend returns an iterator over every element of the set
+// in ascending order.
+func (s *BTreeSet[K]) Ascend() iter.Seq[K] {
+	return keys(s.m.Ascend())
+}
+
+// Descend returns an iterator over every element of the set
+// in descending order.
+func (s *BTreeSet[K]) Descend() iter.Seq[K] {
+	return keys(s.m.Descend())
+}
+
+// Range returns an iterator over every element of the set that
+// is in the half-open range [lo, hi), in ascending order.
+func (s *BTreeSet[K]) Range(lo K, hi K) iter.Seq[K] {
+	return keys(s.m.Range(lo, hi))
+}
+
+func keys[K cmp.Ordered](seq iter.Seq2[K, struct{}]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
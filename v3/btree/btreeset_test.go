@@ -0,0 +1,59 @@
+package btree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s, err := NewSet[string]()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.PutSlice([]string{"pear", "apple", "fig", "apple"})
+	s.Put("kiwi")
+	if s.Len() != 4 {
+		t.Errorf("Expected length 4, got %v", s.Len())
+	}
+	if !s.Has("fig") {
+		t.Error("Expected fig to be in the set")
+	}
+	if !s.Delete("fig") || s.Has("fig") {
+		t.Error("Expected to delete fig")
+	}
+	var got []string
+	s.Iter(func(elem string) {
+		got = append(got, elem)
+	})
+	if want := []string{"apple", "kiwi", "pear"}; !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := slices.Collect(s.Descend()); !slices.Equal([]string{"pear", "kiwi", "apple"}, got) {
+		t.Errorf("Expected descent pear kiwi apple, got %v", got)
+	}
+	if floor, _ := s.Floor("banana"); floor != "apple" {
+		t.Errorf("Expected floor apple, got %v", floor)
+	}
+	if ceiling, _ := s.Ceiling("banana"); ceiling != "kiwi" {
+		t.Errorf("Expected ceiling kiwi, got %v", ceiling)
+	}
+	if min, _ := s.Min(); min != "apple" {
+		t.Errorf("Expected min apple, got %v", min)
+	}
+	if max, _ := s.Max(); max != "pear" {
+		t.Errorf("Expected max pear, got %v", max)
+	}
+}
+
+func TestSetFromSorted(t *testing.T) {
+	s, err := SetFromSorted(3, []int{1, 2, 3, 5, 8, 13, 21, 34})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := slices.Collect(s.Range(3, 21)); !slices.Equal([]int{3, 5, 8, 13}, got) {
+		t.Errorf("Expected range 3 5 8 13, got %v", got)
+	}
+	if _, err := SetFromSorted(3, []int{2, 2}); err == nil {
+		t.Error("Expected an error for repeated keys")
+	}
+}
@@ -0,0 +1,305 @@
+// Package extsort implements an external merge sort, for sorting
+// more records than fit in memory.
+//
+// Records are read from an io.Reader into memory until the memory
+// budget is used up. That chunk is sorted and written to a
+// temporary file as a sorted run, and so on until the input is
+// exhausted. The runs are then k-way merged with package merge
+// and written to an io.Writer. If there are more runs than can
+// sensibly be held open at once, they are merged in several
+// passes, at most MaxFanIn runs at a time.
+//
+// How records are read and written is up to a Codec, and how they
+// are ordered is up to a comparison function, so any record type
+// can be sorted. Lines is a ready-made Codec for text files.
+package extsort
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"slices"
+
+	"github.com/manniwood/mmmdatastructures/v3/merge"
+	"github.com/manniwood/mmmdatastructures/v3/sorting"
+)
+
+// DefaultMemoryBudget is the memory budget, in bytes, used when
+// Config.MemoryBudget is zero.
+const DefaultMemoryBudget = 64 << 20
+
+// DefaultMaxFanIn is the most runs merged at once, used when
+// Config.MaxFanIn is zero.
+const DefaultMaxFanIn = 64
+
+var MissingCodec = errors.New("Missing Codec")
+var MissingCompare = errors.New("Missing Compare Function")
+var InvalidMaxFanIn = errors.New("Max Fan In Must Be At Least 2")
+
+// Codec reads and writes records of type T.
+type Codec[T any] interface {
+	// Read reads the next record. It returns io.EOF, and only
+	// io.EOF, when there are no more records.
+	Read(r *bufio.Reader) (T, error)
+	// Write writes a record so that Read can read it back.
+	Write(w *bufio.Writer, rec T) error
+	// Size estimates how many bytes of memory rec takes up.
+	// It is used to keep each in-memory chunk within the
+	// memory budget.
+	Size(rec T) int
+}
+
+// Config holds the settings for Sort.
+type Config[T any] struct {
+	// Codec reads records from the input and writes them to the
+	// output and to temporary files. It is required.
+	Codec Codec[T]
+	// Compare orders records. It must return a negative number when
+	// a < b, a positive number when a > b, and zero when a == b.
+	// It is required.
+	Compare func(a, b T) int
+	// MemoryBudget is roughly how many bytes of records to hold in
+	// memory at once, as measured by Codec.Size.
+	// Zero means DefaultMemoryBudget.
+	MemoryBudget int
+	// TempDir is where sorted runs are spilled.
+	// Empty means os.TempDir().
+	TempDir string
+	// MaxFanIn is the most runs merged at once. Zero means
+	// DefaultMaxFanIn.
+	MaxFanIn int
+}
+
+// Sort reads every record from r, and writes them to w in sorted
+// order. Temporary files are removed before Sort returns.
+func Sort[T any](r io.Reader, w io.Writer, cfg Config[T]) error {
+	if cfg.Codec == nil {
+		return MissingCodec
+	}
+	if cfg.Compare == nil {
+		return MissingCompare
+	}
+	if cfg.MemoryBudget <= 0 {
+		cfg.MemoryBudget = DefaultMemoryBudget
+	}
+	if cfg.MaxFanIn == 0 {
+		cfg.MaxFanIn = DefaultMaxFanIn
+	}
+	if cfg.MaxFanIn < 2 {
+		return InvalidMaxFanIn
+	}
+	s := &sorter[T]{cfg: cfg}
+	defer s.cleanup()
+
+	in := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+	for {
+		chunk, err := s.readChunk(in)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		sorting.IntroSortFunc(chunk, cfg.Compare)
+		if err == io.EOF && len(s.runs) == 0 {
+			// Everything fit in memory, so there is no need
+			// to go anywhere near the disk.
+			if err := s.writeAll(out, slices.Values(chunk)); err != nil {
+				return err
+			}
+			return out.Flush()
+		}
+		if len(chunk) > 0 {
+			if err := s.spill(chunk); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	// Merge runs in passes until few enough remain to merge at once.
+	for len(s.runs) > cfg.MaxFanIn {
+		var next []string
+		for i := 0; i < len(s.runs); i += cfg.MaxFanIn {
+			group := s.runs[i:min(i+cfg.MaxFanIn, len(s.runs))]
+			name, err := s.mergeToRun(group)
+			if err != nil {
+				return err
+			}
+			next = append(next, name)
+		}
+		s.runs = next
+	}
+	if err := s.mergeTo(out, s.runs); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// sorter holds the state of a single call to Sort.
+type sorter[T any] struct {
+	cfg     Config[T]
+	tempDir string
+	// runs holds the names of the temporary files holding sorted runs.
+	runs []string
+}
+
+// readChunk reads records until the memory budget is used up.
+// It returns io.EOF along with the last chunk.
+func (s *sorter[T]) readChunk(in *bufio.Reader) ([]T, error) {
+	var chunk []T
+	used := 0
+	for used < s.cfg.MemoryBudget {
+		rec, err := s.cfg.Codec.Read(in)
+		if err != nil {
+			return chunk, err
+		}
+		chunk = append(chunk, rec)
+		used += s.cfg.Codec.Size(rec)
+	}
+	return chunk, nil
+}
+
+// spill writes a sorted chunk to a new temporary file.
+func (s *sorter[T]) spill(chunk []T) error {
+	f, err := s.createRun()
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(f)
+	for _, rec := range chunk {
+		if err := s.cfg.Codec.Write(out, rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := out.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f.Name())
+	return nil
+}
+
+// createRun creates a new temporary file for a run, in a temporary
+// directory that cleanup removes.
+func (s *sorter[T]) createRun() (*os.File, error) {
+	if s.tempDir == "" {
+		dir, err := os.MkdirTemp(s.cfg.TempDir, "extsort-")
+		if err != nil {
+			return nil, err
+		}
+		s.tempDir = dir
+	}
+	return os.CreateTemp(s.tempDir, "run-")
+}
+
+// mergeToRun merges the given runs into a new run, removes them,
+// and returns the name of the new run.
+func (s *sorter[T]) mergeToRun(names []string) (string, error) {
+	f, err := s.createRun()
+	if err != nil {
+		return "", err
+	}
+	out := bufio.NewWriter(f)
+	err = s.mergeTo(out, names)
+	if err == nil {
+		err = out.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	for _, name := range names {
+		os.Remove(name)
+	}
+	return f.Name(), nil
+}
+
+// mergeTo k-way merges the given runs into out.
+func (s *sorter[T]) mergeTo(out *bufio.Writer, names []string) error {
+	var readErr error
+	seqs := make([]iter.Seq[T], 0, len(names))
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in := bufio.NewReader(f)
+		seqs = append(seqs, func(yield func(T) bool) {
+			for {
+				rec, err := s.cfg.Codec.Read(in)
+				if err != nil {
+					if err != io.EOF && readErr == nil {
+						readErr = err
+					}
+					return
+				}
+				if !yield(rec) {
+					return
+				}
+			}
+		})
+	}
+	if err := s.writeAll(out, merge.MergeFunc(s.cfg.Compare, seqs...)); err != nil {
+		return err
+	}
+	return readErr
+}
+
+// writeAll writes every record in seq to out.
+func (s *sorter[T]) writeAll(out *bufio.Writer, seq iter.Seq[T]) error {
+	for rec := range seq {
+		if err := s.cfg.Codec.Write(out, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanup removes every temporary file.
+func (s *sorter[T]) cleanup() {
+	if s.tempDir != "" {
+		os.RemoveAll(s.tempDir)
+	}
+}
+
+// Lines is a Codec for newline-terminated lines of text. The
+// newline is not part of the record; a final line with no
+// newline is still read as a record.
+type Lines struct{}
+
+// Read reads the next line, without its trailing newline.
+func (Lines) Read(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF && len(line) > 0 {
+		return line, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return line[:len(line)-1], nil
+}
+
+// Write writes rec followed by a newline.
+func (Lines) Write(w *bufio.Writer, rec string) error {
+	if _, err := w.WriteString(rec); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+// Size estimates the memory used by rec: its bytes plus the
+// string header.
+func (Lines) Size(rec string) int {
+	return len(rec) + 16
+}
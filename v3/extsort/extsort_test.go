@@ -0,0 +1,132 @@
+package extsort
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSortLinesInMemory(t *testing.T) {
+	in := strings.NewReader("pear\napple\nfig\nbanana")
+	var out bytes.Buffer
+	err := Sort[string](in, &out, Config[string]{
+		Codec:   Lines{},
+		Compare: strings.Compare,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "apple\nbanana\nfig\npear\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
+
+func TestSortLinesSpilled(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var lines []string
+	for i := 0; i < 5000; i++ {
+		lines = append(lines, fmt.Sprintf("line-%d", r.Intn(100000)))
+	}
+	in := strings.NewReader(strings.Join(lines, "\n") + "\n")
+	tempDir := t.TempDir()
+	var out bytes.Buffer
+	// A tiny budget and fan in, so that there are many runs
+	// and several merge passes.
+	err := Sort[string](in, &out, Config[string]{
+		Codec:        Lines{},
+		Compare:      strings.Compare,
+		MemoryBudget: 1000,
+		TempDir:      tempDir,
+		MaxFanIn:     4,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	slices.Sort(lines)
+	want := strings.Join(lines, "\n") + "\n"
+	if out.String() != want {
+		t.Error("Output was not the sorted input")
+	}
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 0 {
+		t.Errorf("Expected temporary files to be cleaned up, found %v", len(entries))
+	}
+}
+
+// int64s is a Codec for fixed-width big-endian int64 records.
+type int64s struct{}
+
+func (int64s) Read(r *bufio.Reader) (int64, error) {
+	var x int64
+	// binary.Read returns io.EOF only if no bytes were read.
+	err := binary.Read(r, binary.BigEndian, &x)
+	return x, err
+}
+
+func (int64s) Write(w *bufio.Writer, rec int64) error {
+	return binary.Write(w, binary.BigEndian, rec)
+}
+
+func (int64s) Size(rec int64) int {
+	return 8
+}
+
+func TestSortCustomCodec(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var in bytes.Buffer
+	var want []int64
+	for i := 0; i < 1000; i++ {
+		x := r.Int63() - r.Int63()
+		want = append(want, x)
+		binary.Write(&in, binary.BigEndian, x)
+	}
+	// Sort descending.
+	desc := func(a, b int64) int {
+		switch {
+		case a > b:
+			return -1
+		case a < b:
+			return 1
+		}
+		return 0
+	}
+	var out bytes.Buffer
+	err := Sort[int64](&in, &out, Config[int64]{
+		Codec:        int64s{},
+		Compare:      desc,
+		MemoryBudget: 800,
+		TempDir:      t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	slices.SortFunc(want, desc)
+	got := make([]int64, len(want))
+	binary.Read(&out, binary.BigEndian, got)
+	if !slices.Equal(want, got) {
+		t.Error("Output was not the input sorted in descending order")
+	}
+}
+
+func TestSortConfigErrors(t *testing.T) {
+	var out bytes.Buffer
+	err := Sort[string](strings.NewReader(""), &out, Config[string]{Compare: strings.Compare})
+	if err != MissingCodec {
+		t.Errorf("Expected MissingCodec, got %v", err)
+	}
+	err = Sort[string](strings.NewReader(""), &out, Config[string]{Codec: Lines{}})
+	if err != MissingCompare {
+		t.Errorf("Expected MissingCompare, got %v", err)
+	}
+	err = Sort[string](strings.NewReader(""), &out, Config[string]{Codec: Lines{}, Compare: strings.Compare, MaxFanIn: 1})
+	if err != InvalidMaxFanIn {
+		t.Errorf("Expected InvalidMaxFanIn, got %v", err)
+	}
+}
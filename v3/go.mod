@@ -1,3 +1,3 @@
 module github.com/manniwood/mmmdatastructures/v3
 
-go 1.18
+go 1.23
@@ -2,7 +2,7 @@
 package maxheap
 
 import (
-	"constraints"
+	"cmp"
 	"errors"
 	"fmt"
 
@@ -33,20 +33,20 @@ var HeapCapacityExceeded = errors.New("Heap Capacity Exceeded")
 var HeapEmpty = errors.New("Heap Empty")
 
 // MaxHeap holds the data and state of the max heap.
-type MaxHeap[T constraints.Ordered] struct {
+type MaxHeap[T cmp.Ordered] struct {
 	data     []T
 	capacity int
 	size     int
 }
 
 // New returns a new empty max heap of the default capacity.
-func New[T constraints.Ordered]() (*MaxHeap[T], error) {
+func New[T cmp.Ordered]() (*MaxHeap[T], error) {
 	return NewWithCapacity[T](DefaultCapacity)
 }
 
 // NewWithCapacity returns a new empty max heap with the requested capacity
 // rounded up to the next power of two.
-func NewWithCapacity[T constraints.Ordered](requested int) (*MaxHeap[T], error) {
+func NewWithCapacity[T cmp.Ordered](requested int) (*MaxHeap[T], error) {
 	if requested < 1 {
 		return nil, &NegativeHeapCapacityError{
 			msg: fmt.Sprintf("requested capacity %d is zero or negative", requested),
@@ -72,7 +72,9 @@ func NewWithCapacity[T constraints.Ordered](requested int) (*MaxHeap[T], error)
 // of the max heap cannot be grown any more to accommodate
 // the added item.
 func (h *MaxHeap[T]) Insert(elem T) error {
-	if h.size+1 > h.capacity {
+	// The backing slice does not use index 0, so it is full
+	// once size+1 reaches capacity.
+	if h.size+1 >= h.capacity {
 		newCapacity := h.capacity * 2
 		// if newCapacity became negative, we have exceeded
 		// our capacity by doing one bit-shift too far
@@ -172,7 +174,7 @@ func (h *MaxHeap[T]) Delete() (T, error) {
 	return max, nil
 }
 
-func sink[T constraints.Ordered](data []T, parent int, size int) {
+func sink[T cmp.Ordered](data []T, parent int, size int) {
 	for parent*2 <= size {
 		// Make child the index of the larger of the parent's two children.
 		// But, only check the right child when one exists, otherwise we
@@ -192,7 +194,7 @@ func sink[T constraints.Ordered](data []T, parent int, size int) {
 }
 
 // Sort performs an in-place heap sort on the provided slice.
-func Sort[T constraints.Ordered](data []T) {
+func Sort[T cmp.Ordered](data []T) {
 	if data == nil || len(data) <= 2 {
 		return
 	}
@@ -35,6 +35,22 @@ func TestInsertInt(t *testing.T) {
 	}
 }
 
+func TestFillInt(t *testing.T) {
+	h, _ := New[int]()
+	for i := 1; i <= 100; i++ {
+		h.Insert(i)
+	}
+	if h.Size() != 100 {
+		t.Errorf("Expected size to be 100, got %v", h.Size())
+	}
+	for i := 100; i >= 1; i-- {
+		got, _ := h.Delete()
+		if got != i {
+			t.Errorf("Expected max to be %v, got %v", i, got)
+		}
+	}
+}
+
 func checkBackingSliceInt(t *testing.T, a []int, b []int, sz int) {
 	expectedSize := len(a) - 1
 	if sz != expectedSize {
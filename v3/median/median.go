@@ -0,0 +1,336 @@
+// Package median implements a running median and a running quantile
+// over a stream of samples.
+//
+// Both are built on a pair of heaps: a max heap holding the
+// lower part of the samples seen so far, and a min heap holding
+// the upper part. The answer is always sitting at the top of one
+// (or both) of the heaps, so adding a sample and asking for the
+// current median are both O(log n).
+//
+// Samples can also be removed again, which is what you need for
+// a sliding window. Because neither heap can delete anything but
+// its top element, removal is lazy: the removed sample is
+// remembered in a bag, and only actually thrown away once it
+// rises to the top of its heap.
+package median
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/manniwood/mmmdatastructures/v3/bag"
+	"github.com/manniwood/mmmdatastructures/v3/maxheap"
+	"github.com/manniwood/mmmdatastructures/v3/minheap"
+)
+
+type InvalidQuantileError struct {
+	msg string
+}
+
+func (e *InvalidQuantileError) Error() string {
+	return e.msg
+}
+
+var NoSamples = errors.New("No Samples")
+var SampleNotFound = errors.New("Sample Not Found")
+
+// Number is the set of types whose median can be computed by
+// averaging the two middle samples.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// twoHeaps holds the samples split between a lower max heap
+// and an upper min heap. Every sample in lower is <= every
+// sample in upper. lowerTarget tells us, for a given number
+// of samples, how many of them belong in lower.
+type twoHeaps[T cmp.Ordered] struct {
+	lower *maxheap.MaxHeap[T]
+	upper *minheap.MinHeap[T]
+	// lowerLen and upperLen are the number of live samples in
+	// each heap; the heaps themselves may also still hold
+	// samples that have been removed but not yet thrown away.
+	lowerLen int
+	upperLen int
+	// lowerGone and upperGone count the samples that have been
+	// removed from each heap but not yet thrown away.
+	lowerGone bag.Bag[T]
+	upperGone bag.Bag[T]
+	// live counts every sample that has been added and not yet
+	// removed, so that Remove can refuse to remove a sample that
+	// is not there.
+	live        bag.Bag[T]
+	lowerTarget func(n int) int
+}
+
+func newTwoHeaps[T cmp.Ordered](lowerTarget func(n int) int) (*twoHeaps[T], error) {
+	lower, err := maxheap.New[T]()
+	if err != nil {
+		return nil, err
+	}
+	upper, err := minheap.New[T]()
+	if err != nil {
+		return nil, err
+	}
+	return &twoHeaps[T]{
+		lower:       lower,
+		upper:       upper,
+		lowerGone:   bag.New[T](),
+		upperGone:   bag.New[T](),
+		live:        bag.New[T](),
+		lowerTarget: lowerTarget,
+	}, nil
+}
+
+// Len returns the number of samples currently being tracked.
+func (h *twoHeaps[T]) Len() int {
+	return h.lowerLen + h.upperLen
+}
+
+// Add adds a sample. It returns an error if one of the backing
+// heaps cannot grow any more.
+func (h *twoHeaps[T]) Add(x T) error {
+	h.live.Put(x)
+	if h.lowerLen == 0 {
+		if err := h.lower.Insert(x); err != nil {
+			return err
+		}
+		h.lowerLen++
+		return h.rebalance()
+	}
+	top, _ := h.lower.Peek()
+	if x <= top {
+		if err := h.lower.Insert(x); err != nil {
+			return err
+		}
+		h.lowerLen++
+	} else {
+		if err := h.upper.Insert(x); err != nil {
+			return err
+		}
+		h.upperLen++
+	}
+	return h.rebalance()
+}
+
+// Remove removes one copy of a previously added sample. It returns
+// SampleNotFound if no live copy of x is being tracked.
+func (h *twoHeaps[T]) Remove(x T) error {
+	if !h.live.Has(x) {
+		return SampleNotFound
+	}
+	h.live.Delete(x)
+	// Every sample in lower is <= the top of lower, and every
+	// sample in upper is >= it, so if x is <= the top of lower,
+	// a copy of x is certainly in lower.
+	top, _ := h.lower.Peek()
+	if x <= top {
+		h.lowerGone.Put(x)
+		h.lowerLen--
+		h.pruneLower()
+	} else {
+		h.upperGone.Put(x)
+		h.upperLen--
+		h.pruneUpper()
+	}
+	return h.rebalance()
+}
+
+// pruneLower throws away removed samples from the top of lower,
+// so that the top of lower is always a live sample.
+func (h *twoHeaps[T]) pruneLower() {
+	for h.lower.Size() > 0 {
+		top, _ := h.lower.Peek()
+		if !h.lowerGone.Has(top) {
+			return
+		}
+		h.lowerGone.Delete(top)
+		h.lower.Delete()
+	}
+}
+
+// pruneUpper throws away removed samples from the top of upper,
+// so that the top of upper is always a live sample.
+func (h *twoHeaps[T]) pruneUpper() {
+	for h.upper.Size() > 0 {
+		top, _ := h.upper.Peek()
+		if !h.upperGone.Has(top) {
+			return
+		}
+		h.upperGone.Delete(top)
+		h.upper.Delete()
+	}
+}
+
+// rebalance moves samples between the heaps until lower holds
+// exactly as many live samples as lowerTarget asks for.
+func (h *twoHeaps[T]) rebalance() error {
+	want := h.lowerTarget(h.Len())
+	for h.lowerLen > want {
+		x, _ := h.lower.Delete()
+		h.lowerLen--
+		h.pruneLower()
+		if err := h.upper.Insert(x); err != nil {
+			return err
+		}
+		h.upperLen++
+	}
+	for h.lowerLen < want {
+		x, _ := h.upper.Delete()
+		h.upperLen--
+		h.pruneUpper()
+		if err := h.lower.Insert(x); err != nil {
+			return err
+		}
+		h.lowerLen++
+	}
+	return nil
+}
+
+// RunningMedian tracks the median of a stream of samples.
+type RunningMedian[T Number] struct {
+	h *twoHeaps[T]
+}
+
+// NewRunningMedian returns a new empty running median.
+func NewRunningMedian[T Number]() (*RunningMedian[T], error) {
+	// For an odd number of samples, lower holds the middle one.
+	h, err := newTwoHeaps[T](func(n int) int { return (n + 1) / 2 })
+	if err != nil {
+		return nil, err
+	}
+	return &RunningMedian[T]{h: h}, nil
+}
+
+// Add adds a sample.
+func (m *RunningMedian[T]) Add(x T) error {
+	return m.h.Add(x)
+}
+
+// Remove removes one copy of a previously added sample, which is
+// useful for maintaining the median of a sliding window. It returns
+// SampleNotFound if x is not currently being tracked.
+func (m *RunningMedian[T]) Remove(x T) error {
+	return m.h.Remove(x)
+}
+
+// Len returns the number of samples currently being tracked.
+func (m *RunningMedian[T]) Len() int {
+	return m.h.Len()
+}
+
+// Median returns the current median. For an even number of samples,
+// it is the mean of the two middle samples. It returns NoSamples
+// if there are no samples.
+func (m *RunningMedian[T]) Median() (float64, error) {
+	n := m.h.Len()
+	if n == 0 {
+		return 0, NoSamples
+	}
+	lo, _ := m.h.lower.Peek()
+	if n%2 == 1 {
+		return float64(lo), nil
+	}
+	hi, _ := m.h.upper.Peek()
+	return float64(lo)/2 + float64(hi)/2, nil
+}
+
+// RunningQuantile tracks a fixed quantile q of a stream of samples,
+// using the nearest-rank method: the q-quantile of n samples is the
+// sample at rank ceil(q*n) in sorted order (counting from 1, and
+// never lower than 1).
+//
+// q is taken to be the shortest decimal that converts to it, as
+// strconv.FormatFloat prints it, so that 0.07 means exactly 7/100
+// rather than the float64 nearest to it, which is slightly more.
+// The rank is then worked out exactly, so the 0.07 quantile of 100
+// samples is the 7th, not the 8th.
+type RunningQuantile[T cmp.Ordered] struct {
+	h *twoHeaps[T]
+	q float64
+}
+
+// NewRunningQuantile returns a new empty running quantile for q,
+// which must be between 0 and 1 inclusive.
+func NewRunningQuantile[T cmp.Ordered](q float64) (*RunningQuantile[T], error) {
+	if !(q >= 0 && q <= 1) {
+		return nil, &InvalidQuantileError{
+			msg: fmt.Sprintf("quantile %v is not between 0 and 1", q),
+		}
+	}
+	h, err := newTwoHeaps[T](nearestRank(q))
+	if err != nil {
+		return nil, err
+	}
+	return &RunningQuantile[T]{h: h, q: q}, nil
+}
+
+// nearestRank returns a function giving the rank, counting from 1,
+// of the q-quantile of n samples, or 0 for no samples.
+func nearestRank(q float64) func(n int) int {
+	// q is between 0 and 1, so this always parses, and always has
+	// num <= den.
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(q, 'g', -1, 64))
+	num, den := r.Num(), r.Denom()
+	small := num.IsUint64() && den.IsUint64()
+	return func(n int) int {
+		if n == 0 {
+			return 0
+		}
+		var rank int
+		if small {
+			// num*n fits in 128 bits, and since num <= den, the
+			// high half is less than den, as Div64 needs.
+			hi, lo := bits.Mul64(num.Uint64(), uint64(n))
+			quo, rem := bits.Div64(hi, lo, den.Uint64())
+			rank = int(quo)
+			if rem != 0 {
+				rank++
+			}
+		} else {
+			quo, rem := new(big.Int).QuoRem(new(big.Int).Mul(num, big.NewInt(int64(n))), den, new(big.Int))
+			rank = int(quo.Int64())
+			if rem.Sign() != 0 {
+				rank++
+			}
+		}
+		return max(rank, 1)
+	}
+}
+
+// Add adds a sample.
+func (r *RunningQuantile[T]) Add(x T) error {
+	return r.h.Add(x)
+}
+
+// Remove removes one copy of a previously added sample. It returns
+// SampleNotFound if x is not currently being tracked.
+func (r *RunningQuantile[T]) Remove(x T) error {
+	return r.h.Remove(x)
+}
+
+// Len returns the number of samples currently being tracked.
+func (r *RunningQuantile[T]) Len() int {
+	return r.h.Len()
+}
+
+// Q returns the quantile being tracked.
+func (r *RunningQuantile[T]) Q() float64 {
+	return r.q
+}
+
+// Quantile returns the sample at the tracked quantile. It returns
+// NoSamples if there are no samples.
+func (r *RunningQuantile[T]) Quantile() (T, error) {
+	if r.h.Len() == 0 {
+		var zero T
+		return zero, NoSamples
+	}
+	return r.h.lower.Peek()
+}
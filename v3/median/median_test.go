@@ -0,0 +1,178 @@
+package median
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestMedian(t *testing.T) {
+	m, _ := NewRunningMedian[int]()
+	_, err := m.Median()
+	if err != NoSamples {
+		t.Errorf("Expected NoSamples, got %v", err)
+	}
+	var tests = []struct {
+		add  int
+		want float64
+	}{
+		{5, 5},
+		{15, 10},
+		{1, 5},
+		{3, 4},
+		{8, 5},
+		{7, 6},
+	}
+	for _, test := range tests {
+		m.Add(test.add)
+		got, _ := m.Median()
+		if got != test.want {
+			t.Errorf("After adding %v, expected median %v, got %v", test.add, test.want, got)
+		}
+	}
+}
+
+func TestMedianRemove(t *testing.T) {
+	m, _ := NewRunningMedian[int]()
+	for _, x := range []int{1, 2, 3, 4, 5} {
+		m.Add(x)
+	}
+	if err := m.Remove(6); err != SampleNotFound {
+		t.Errorf("Expected SampleNotFound, got %v", err)
+	}
+	m.Remove(3)
+	got, _ := m.Median()
+	if got != 3 {
+		t.Errorf("Expected median 3, got %v", got)
+	}
+	m.Remove(1)
+	m.Remove(2)
+	got, _ = m.Median()
+	if got != 4.5 {
+		t.Errorf("Expected median 4.5, got %v", got)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Expected length 2, got %v", m.Len())
+	}
+}
+
+// TestSlidingWindow checks the running median and quantile of a
+// sliding window of random samples against a brute-force sort.
+func TestSlidingWindow(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const window = 25
+	m, _ := NewRunningMedian[int]()
+	q90, _ := NewRunningQuantile[int](0.9)
+	var samples []int
+	for i := 0; i < 2000; i++ {
+		x := r.Intn(50)
+		samples = append(samples, x)
+		m.Add(x)
+		q90.Add(x)
+		if len(samples) > window {
+			old := samples[len(samples)-window-1]
+			if err := m.Remove(old); err != nil {
+				t.Fatalf("Unexpected error removing %v: %v", old, err)
+			}
+			q90.Remove(old)
+		}
+		start := len(samples) - window
+		if start < 0 {
+			start = 0
+		}
+		sorted := append([]int(nil), samples[start:]...)
+		sort.Ints(sorted)
+		n := len(sorted)
+		var want float64
+		if n%2 == 1 {
+			want = float64(sorted[n/2])
+		} else {
+			want = float64(sorted[n/2-1]+sorted[n/2]) / 2
+		}
+		got, _ := m.Median()
+		if got != want {
+			t.Fatalf("Step %v: expected median %v, got %v", i, want, got)
+		}
+		rank := (9*n + 9) / 10
+		gotQ, _ := q90.Quantile()
+		if gotQ != sorted[rank-1] {
+			t.Fatalf("Step %v: expected 0.9 quantile %v, got %v", i, sorted[rank-1], gotQ)
+		}
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	_, err := NewRunningQuantile[int](1.5)
+	if err == nil {
+		t.Error("Expected error for quantile outside [0, 1]")
+	}
+	q, _ := NewRunningQuantile[string](0)
+	q.Add("b")
+	q.Add("a")
+	q.Add("c")
+	got, _ := q.Quantile()
+	if got != "a" {
+		t.Errorf("Expected 0 quantile to be the minimum, got %v", got)
+	}
+	q, _ = NewRunningQuantile[string](1)
+	q.Add("b")
+	q.Add("a")
+	q.Add("c")
+	got, _ = q.Quantile()
+	if got != "c" {
+		t.Errorf("Expected 1 quantile to be the maximum, got %v", got)
+	}
+}
+
+// TestQuantileRank checks the rank for values of q*n that should be
+// whole numbers but do not come out as such in floating point.
+func TestQuantileRank(t *testing.T) {
+	var tests = []struct {
+		q    float64
+		n    int
+		want int
+	}{
+		{0.07, 100, 7},
+		{0.14, 50, 7},
+		{0.29, 100, 29},
+		{0.5, 3, 2},
+		{0.9, 25, 23},
+		{0.01, 1, 1},
+	}
+	for _, test := range tests {
+		q, _ := NewRunningQuantile[int](test.q)
+		for i := 1; i <= test.n; i++ {
+			q.Add(i)
+		}
+		if got, _ := q.Quantile(); got != test.want {
+			t.Errorf("Expected %v quantile of 1..%v to be %v, got %v", test.q, test.n, test.want, got)
+		}
+	}
+}
+
+// TestNearestRank checks ranks that are too large to test by adding
+// samples, where a q*n just above a whole number has a fractional
+// part far below the rounding error of the float64 product.
+func TestNearestRank(t *testing.T) {
+	var tests = []struct {
+		q    float64
+		n    int
+		want int
+	}{
+		{0.07, 100, 7},
+		{0.14, 50, 7},
+		{0.1, 1_000_000_000_000_001, 100_000_000_000_001},
+		{0.1, 1_000_000_000_000_000, 100_000_000_000_000},
+		{0.07, 1 << 62, 322818021289917154},
+		{0.999, 1 << 62, 4607074332408960517},
+		{1, 1 << 62, 1 << 62},
+		{0, 1 << 62, 1},
+		{1e-30, 5, 1},
+		{1e-30, 0, 0},
+	}
+	for _, test := range tests {
+		if got := nearestRank(test.q)(test.n); got != test.want {
+			t.Errorf("Expected rank %v for q %v of %v, got %v", test.want, test.q, test.n, got)
+		}
+	}
+}
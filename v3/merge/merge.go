@@ -0,0 +1,147 @@
+// Package merge implements a k-way merge of sorted sequences.
+//
+// The head of every input sequence is kept in a binary min heap,
+// laid out the same way as in package maxheap (index 0 of the
+// backing slice is unused, and the children of i are 2i and 2i+1).
+// Each step yields the top of the heap, pulls the next element from
+// the sequence it came from, and sinks that element down to its
+// place in the heap. Merging n elements from k sequences is
+// therefore O(n log k).
+//
+// The merge is stable: elements that compare as equal are yielded
+// in the order of the sequences they came from.
+package merge
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Merge merges sequences that are each sorted in ascending order
+// into one sequence sorted in ascending order.
+func Merge[T cmp.Ordered](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(cmp.Compare[T], false, seqs)
+}
+
+// MergeFunc is like Merge, but orders elements using the comparison
+// function cmp, which must return a negative number when a < b,
+// a positive number when a > b, and zero when a == b.
+func MergeFunc[T any](cmp func(a, b T) int, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(cmp, false, seqs)
+}
+
+// Unique is like Merge, but only yields the first of each run of
+// equal elements, so that the merged sequence has no duplicates.
+func Unique[T cmp.Ordered](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(cmp.Compare[T], true, seqs)
+}
+
+// UniqueFunc is like Unique, but orders and compares elements
+// using the comparison function cmp.
+func UniqueFunc[T any](cmp func(a, b T) int, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(cmp, true, seqs)
+}
+
+// Slices merges slices that are each sorted in ascending order
+// into one slice sorted in ascending order.
+func Slices[T cmp.Ordered](ss ...[]T) []T {
+	return SlicesFunc(cmp.Compare[T], ss...)
+}
+
+// SlicesFunc is like Slices, but orders elements using the
+// comparison function cmp.
+func SlicesFunc[T any](cmp func(a, b T) int, ss ...[]T) []T {
+	total := 0
+	seqs := make([]iter.Seq[T], len(ss))
+	for i, s := range ss {
+		total += len(s)
+		seqs[i] = slices.Values(s)
+	}
+	merged := make([]T, 0, total)
+	for elem := range merge(cmp, false, seqs) {
+		merged = append(merged, elem)
+	}
+	return merged
+}
+
+// cursor is the current head of one of the sequences being merged.
+type cursor[T any] struct {
+	head T
+	next func() (T, bool)
+	// src is the position of the sequence in the argument list,
+	// used to break ties so that the merge is stable.
+	src int
+}
+
+func merge[T any](cmp func(a, b T) int, unique bool, seqs []iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		// less reports whether cursor a belongs above cursor b in the heap.
+		less := func(a, b *cursor[T]) bool {
+			c := cmp(a.head, b.head)
+			if c != 0 {
+				return c < 0
+			}
+			return a.src < b.src
+		}
+		// As with maxheap, index 0 is unused.
+		data := make([]*cursor[T], 1, len(seqs)+1)
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			head, ok := next()
+			if !ok {
+				continue
+			}
+			data = append(data, &cursor[T]{head: head, next: next, src: i})
+		}
+		size := len(data) - 1
+		for i := size / 2; i >= 1; i-- {
+			sink(data, i, size, less)
+		}
+
+		var prev T
+		havePrev := false
+		for size > 0 {
+			top := data[1]
+			elem := top.head
+			if !unique || !havePrev || cmp(prev, elem) != 0 {
+				if !yield(elem) {
+					return
+				}
+				prev = elem
+				havePrev = true
+			}
+			head, ok := top.next()
+			if ok {
+				top.head = head
+			} else {
+				// This sequence is used up; replace it with the
+				// last cursor in the heap.
+				data[1] = data[size]
+				data[size] = nil
+				size--
+			}
+			sink(data, 1, size, less)
+		}
+	}
+}
+
+func sink[T any](data []*cursor[T], parent int, size int, less func(a, b *cursor[T]) bool) {
+	for parent*2 <= size {
+		// Make child the index of the smaller of the parent's two children.
+		// But, only check the right child when one exists, otherwise we
+		// are reading past the end of the slice.
+		child := parent * 2
+		if child+1 <= size && less(data[child+1], data[child]) {
+			child++
+		}
+		// swap the child with the parent if the child is smaller
+		if less(data[child], data[parent]) {
+			data[child], data[parent] = data[parent], data[child]
+		} else {
+			break
+		}
+		parent = child
+	}
+}
@@ -0,0 +1,127 @@
+package merge
+
+import (
+	"iter"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	var tests = []struct {
+		input [][]int
+		want  []int
+	}{
+		{[][]int{{1, 4, 7}, {2, 5, 8}, {3, 6, 9}},
+			[]int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{[][]int{{1, 1, 2}, {}, {1, 3}},
+			[]int{1, 1, 1, 2, 3}},
+		{[][]int{{5}},
+			[]int{5}},
+		{[][]int{},
+			[]int{}},
+	}
+	for _, test := range tests {
+		var seqs []iter.Seq[int]
+		for _, s := range test.input {
+			seqs = append(seqs, slices.Values(s))
+		}
+		got := append([]int{}, slices.Collect(Merge(seqs...))...)
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Expected %v, got %v", test.want, got)
+		}
+		got = Slices(test.input...)
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("Expected %v, got %v", test.want, got)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	got := slices.Collect(Unique(
+		slices.Values([]int{1, 1, 2, 5}),
+		slices.Values([]int{1, 3, 5}),
+		slices.Values([]int{2, 5, 6}),
+	))
+	want := []int{1, 2, 3, 5, 6}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestMergeFuncStable(t *testing.T) {
+	// Sorted by length, descending; equal lengths should come out
+	// in the order of the sequences they came from.
+	byLenDesc := func(a, b string) int {
+		return len(b) - len(a)
+	}
+	got := slices.Collect(MergeFunc(byLenDesc,
+		slices.Values([]string{"ccc", "bb", "a"}),
+		slices.Values([]string{"zzz", "yy", "x"}),
+	))
+	want := []string{"ccc", "zzz", "bb", "yy", "a", "x"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	got = slices.Collect(UniqueFunc(strings.Compare,
+		slices.Values([]string{"a", "b"}),
+		slices.Values([]string{"a", "c"}),
+	))
+	want = []string{"a", "b", "c"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestMergeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		var input [][]int
+		var want []int
+		for k := r.Intn(10); k > 0; k-- {
+			s := make([]int, r.Intn(30))
+			for i := range s {
+				s[i] = r.Intn(100)
+			}
+			slices.Sort(s)
+			input = append(input, s)
+			want = append(want, s...)
+		}
+		slices.Sort(want)
+		got := Slices(input...)
+		if !slices.Equal(want, got) {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestMergeStopEarly(t *testing.T) {
+	stopped := 0
+	counting := func(s []int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			defer func() { stopped++ }()
+			for _, x := range s {
+				if !yield(x) {
+					return
+				}
+			}
+		}
+	}
+	var got []int
+	for x := range Merge(counting([]int{1, 3, 5}), counting([]int{2, 4, 6})) {
+		if x > 3 {
+			break
+		}
+		got = append(got, x)
+	}
+	want := []int{1, 2, 3}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if stopped != 2 {
+		t.Errorf("Expected both sequences to be stopped, got %v", stopped)
+	}
+}
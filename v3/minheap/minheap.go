@@ -0,0 +1,195 @@
+// Package minheap implements a binary min heap.
+//
+// It is the mirror image of package maxheap: the smallest
+// element, rather than the largest, sits at the top of the heap.
+package minheap
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"github.com/manniwood/mmmdatastructures/v3"
+)
+
+// DefaultCapacity is the default capacity of the min heap
+// when constructed using New() instead of NewWithCapacity().
+const DefaultCapacity = 32
+
+type NegativeHeapCapacityError struct {
+	msg string
+}
+
+func (e *NegativeHeapCapacityError) Error() string {
+	return e.msg
+}
+
+type ResizeHeapCapacityError struct {
+	msg string
+}
+
+func (e *ResizeHeapCapacityError) Error() string {
+	return e.msg
+}
+
+var HeapCapacityExceeded = errors.New("Heap Capacity Exceeded")
+var HeapEmpty = errors.New("Heap Empty")
+
+// MinHeap holds the data and state of the min heap.
+type MinHeap[T cmp.Ordered] struct {
+	data     []T
+	capacity int
+	size     int
+}
+
+// New returns a new empty min heap of the default capacity.
+func New[T cmp.Ordered]() (*MinHeap[T], error) {
+	return NewWithCapacity[T](DefaultCapacity)
+}
+
+// NewWithCapacity returns a new empty min heap with the requested capacity
+// rounded up to the next power of two.
+func NewWithCapacity[T cmp.Ordered](requested int) (*MinHeap[T], error) {
+	if requested < 1 {
+		return nil, &NegativeHeapCapacityError{
+			msg: fmt.Sprintf("requested capacity %d is zero or negative", requested),
+		}
+	}
+	power := 1
+	for power < requested {
+		power *= 2
+		if power < 0 {
+			// looks like we wrapped
+			power = mmmdatastructures.MaxInt
+			break
+		}
+	}
+	return &MinHeap[T]{
+		data:     make([]T, power, power),
+		capacity: power,
+		size:     0,
+	}, nil
+}
+
+// Insert inserts an item onto the min heap. It returns an error if the size
+// of the min heap cannot be grown any more to accommodate
+// the added item.
+func (h *MinHeap[T]) Insert(elem T) error {
+	if h.size+1 >= h.capacity {
+		newCapacity := h.capacity * 2
+		// if newCapacity became negative, we have exceeded
+		// our capacity by doing one bit-shift too far
+		if newCapacity < 0 {
+			return HeapCapacityExceeded
+		}
+		// NOTE: Purposefully not concerning ourselves
+		// with the error returned from Resize here, because
+		// we know our newCapacity is larger than h.capacity.
+		h.resize(newCapacity)
+	}
+	// Increase the size of the min heap. Usefully, the size
+	// is also the new last index into the backing slice.
+	// Put our new value there. Then, bubble the new value
+	// up, swapping it with its parent, until it is in the
+	// correct position in the min heap.
+	h.size++
+	h.data[h.size] = elem
+	child := h.size
+	for parent := child / 2; parent > 0; parent = child / 2 {
+		if h.data[child] < h.data[parent] {
+			h.data[child], h.data[parent] = h.data[parent], h.data[child]
+		} else {
+			break
+		}
+		child = parent
+	}
+	return nil
+}
+
+// Size returns the current size
+// of the min heap. It also tells you how many
+// slots are being used in the slice that
+// backs the min heap.
+func (h *MinHeap[T]) Size() int {
+	return h.size
+}
+
+// Len is a synonym for Size, so that we can satisfy
+// any interface that might require Len() and Cap(),
+// mimicking the len() and cap() built-ins.
+func (h *MinHeap[T]) Len() int {
+	return h.size
+}
+
+// Cap returns the current capacity of the slice that backs the min heap.
+func (h *MinHeap[T]) Cap() int {
+	return h.capacity
+}
+
+// resize resizes the underlying slice that backs
+// the min heap. It is made private, because we
+// want to enforce resize() only being called with
+// a capacity that is twice the size of the previous
+// capacity.
+func (h *MinHeap[T]) resize(newCapacity int) error {
+	if newCapacity <= h.capacity {
+		return &ResizeHeapCapacityError{
+			msg: fmt.Sprintf("New capacity %d is not larger than current capacity %d", newCapacity, h.capacity),
+		}
+	}
+	newData := make([]T, newCapacity, newCapacity)
+	copy(newData, h.data)
+	h.capacity = newCapacity
+	h.data = newData
+	return nil
+}
+
+// Peek returns the smallest value from the top of the
+// heap, without removing it.
+func (h *MinHeap[T]) Peek() (T, error) {
+	if h.size == 0 {
+		var zero T
+		return zero, HeapEmpty
+	}
+	return h.data[1], nil
+}
+
+// Delete returns the smallest value from the top of the
+// heap, deleting it.
+func (h *MinHeap[T]) Delete() (T, error) {
+	if h.size == 0 {
+		var zero T
+		return zero, HeapEmpty
+	}
+	min := h.data[1]
+	// Take the last item in the heap and make it the
+	// new root, even though this is almost certainly
+	// not the smallest element...
+	h.data[1] = h.data[h.size]
+	h.size--
+
+	parent := 1
+	// ...and "sink" it down to its correct level in the heap.
+	sink(h.data, parent, h.size)
+
+	return min, nil
+}
+
+func sink[T cmp.Ordered](data []T, parent int, size int) {
+	for parent*2 <= size {
+		// Make child the index of the smaller of the parent's two children.
+		// But, only check the right child when one exists, otherwise we
+		// are reading past the end of the slice.
+		child := parent * 2
+		if child+1 <= size && data[child+1] < data[child] {
+			child++
+		}
+		// swap the child with the parent if the child is smaller
+		if data[parent] > data[child] {
+			data[child], data[parent] = data[parent], data[child]
+		} else {
+			break
+		}
+		parent = child
+	}
+}
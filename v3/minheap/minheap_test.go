@@ -0,0 +1,110 @@
+package minheap
+
+import "testing"
+
+func TestCreateInt(t *testing.T) {
+	h, _ := New[int]()
+	if h.size != 0 {
+		t.Error("Expected size to be 0, got ", h.size)
+	}
+}
+
+func TestInsertInt(t *testing.T) {
+	h, _ := New[int]()
+	var tests = []struct {
+		insert int
+		min    int
+		want   []int
+	}{
+		{20, 20, []int{0, 20}},
+		{10, 10, []int{0, 10, 20}},
+		{5, 5, []int{0, 5, 20, 10}},
+		{7, 5, []int{0, 5, 7, 10, 20}},
+	}
+	_, err := h.Peek()
+	if err == nil {
+		t.Error("Supposed to return error when peeking at empty min heap")
+	}
+	for _, test := range tests {
+		h.Insert(test.insert)
+		checkBackingSliceInt(t, test.want, h.data, h.size)
+		i, _ := h.Peek()
+		if i != test.min {
+			t.Errorf("Expected min to be %v, got %v", test.min, i)
+		}
+	}
+}
+
+func checkBackingSliceInt(t *testing.T, a []int, b []int, sz int) {
+	expectedSize := len(a) - 1
+	if sz != expectedSize {
+		t.Errorf("Expected size to be %v, got %v", expectedSize, sz)
+	}
+	for i, x := range a {
+		if x != b[i] {
+			t.Errorf("Expected %vth, element to be %v, got %v", i, x, b[i])
+		}
+	}
+}
+
+func TestDeleteInt(t *testing.T) {
+	h, _ := New[int]()
+	inits := []int{20, 10, 5, 7}
+	for _, i := range inits {
+		h.Insert(i)
+	}
+	var tests = []struct {
+		min  int
+		want []int
+	}{
+		{5, []int{0, 7, 20, 10}},
+		{7, []int{0, 10, 20}},
+		{10, []int{0, 20}},
+		{20, []int{0}},
+	}
+	for _, test := range tests {
+		i, _ := h.Delete()
+		if i != test.min {
+			t.Errorf("Expected min to be %v, got %v", test.min, i)
+		}
+		checkBackingSliceInt(t, test.want, h.data, h.size)
+	}
+	_, err := h.Delete()
+	if err == nil {
+		t.Error("Supposed to return error when deleting from empty min heap")
+	}
+}
+
+func TestFillInt(t *testing.T) {
+	h, _ := New[int]()
+	for i := 100; i >= 1; i-- {
+		h.Insert(i)
+	}
+	if h.Size() != 100 {
+		t.Errorf("Expected size to be 100, got %v", h.Size())
+	}
+	for i := 1; i <= 100; i++ {
+		got, _ := h.Delete()
+		if got != i {
+			t.Errorf("Expected min to be %v, got %v", i, got)
+		}
+	}
+}
+
+func TestDeleteString(t *testing.T) {
+	h, _ := New[string]()
+	inits := []string{"20", "10", "05", "07"}
+	for _, i := range inits {
+		h.Insert(i)
+	}
+	for _, want := range []string{"05", "07", "10", "20"} {
+		got, _ := h.Delete()
+		if got != want {
+			t.Errorf("Expected min to be %v, got %v", want, got)
+		}
+	}
+	_, err := h.Delete()
+	if err == nil {
+		t.Error("Supposed to return error when deleting from empty min heap")
+	}
+}
@@ -0,0 +1,334 @@
+// Package multiset implements a sorted multiset with order
+// statistics.
+//
+// Like bag.Bag, a Multiset counts how many copies of each element
+// it holds, but it also keeps its elements in order, so that it can
+// tell you how many elements are smaller than a given one, which
+// element is the k-th smallest, and which elements are closest to
+// a given one. Every operation takes O(log n) expected time, where
+// n is the number of distinct elements.
+//
+// It is a treap: a binary search tree in which every node also has
+// a random priority, and every node's priority is at least that of
+// its children. That keeps the tree balanced with high probability
+// no matter what order elements are inserted in.
+package multiset
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+
+	"github.com/manniwood/mmmdatastructures/v3/bag"
+)
+
+// Multiset holds the root of the treap.
+type Multiset[T cmp.Ordered] struct {
+	root *node[T]
+}
+
+// node holds count copies of elem. size is the total number of
+// copies held by the subtree rooted at the node.
+type node[T cmp.Ordered] struct {
+	elem     T
+	count    int
+	size     int
+	priority uint64
+	left     *node[T]
+	right    *node[T]
+}
+
+func (n *node[T]) subtreeSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[T]) fix() {
+	n.size = n.left.subtreeSize() + n.count + n.right.subtreeSize()
+}
+
+// New returns a new empty multiset.
+func New[T cmp.Ordered]() *Multiset[T] {
+	return &Multiset[T]{}
+}
+
+// FromBag returns a new multiset holding the same elements as b,
+// with the same number of copies of each.
+func FromBag[T cmp.Ordered](b bag.Bag[T]) *Multiset[T] {
+	m := New[T]()
+	for elem, count := range b {
+		m.InsertN(elem, count)
+	}
+	return m
+}
+
+// Len returns the number of elements in the multiset, counting
+// every copy.
+func (m *Multiset[T]) Len() int {
+	return m.root.subtreeSize()
+}
+
+// Has tells you whether the multiset holds at least one copy of elem.
+func (m *Multiset[T]) Has(elem T) bool {
+	return m.Count(elem) > 0
+}
+
+// Count returns the number of copies of elem in the multiset.
+func (m *Multiset[T]) Count(elem T) int {
+	n := m.root
+	for n != nil {
+		switch c := cmp.Compare(elem, n.elem); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.count
+		}
+	}
+	return 0
+}
+
+// Insert puts one copy of elem in the multiset.
+func (m *Multiset[T]) Insert(elem T) {
+	m.InsertN(elem, 1)
+}
+
+// InsertN puts n copies of elem in the multiset. It does nothing
+// if n is zero or negative.
+func (m *Multiset[T]) InsertN(elem T, n int) {
+	if n <= 0 {
+		return
+	}
+	m.root = insert(m.root, elem, n)
+}
+
+func insert[T cmp.Ordered](n *node[T], elem T, count int) *node[T] {
+	if n == nil {
+		return &node[T]{elem: elem, count: count, size: count, priority: rand.Uint64()}
+	}
+	switch c := cmp.Compare(elem, n.elem); {
+	case c < 0:
+		n.left = insert(n.left, elem, count)
+		if n.left.priority > n.priority {
+			n = rotateRight(n)
+		}
+	case c > 0:
+		n.right = insert(n.right, elem, count)
+		if n.right.priority > n.priority {
+			n = rotateLeft(n)
+		}
+	default:
+		n.count += count
+	}
+	n.fix()
+	return n
+}
+
+// rotateRight makes the left child of n the root of the subtree.
+func rotateRight[T cmp.Ordered](n *node[T]) *node[T] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.fix()
+	l.fix()
+	return l
+}
+
+// rotateLeft makes the right child of n the root of the subtree.
+func rotateLeft[T cmp.Ordered](n *node[T]) *node[T] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.fix()
+	r.fix()
+	return r
+}
+
+// Delete takes one copy of elem out of the multiset. It returns
+// false if the multiset holds no copies of elem.
+func (m *Multiset[T]) Delete(elem T) bool {
+	return m.DeleteN(elem, 1) == 1
+}
+
+// DeleteN takes up to n copies of elem out of the multiset, and
+// returns the number of copies it took out.
+func (m *Multiset[T]) DeleteN(elem T, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	var deleted int
+	m.root = remove(m.root, elem, n, &deleted)
+	return deleted
+}
+
+func remove[T cmp.Ordered](n *node[T], elem T, count int, deleted *int) *node[T] {
+	if n == nil {
+		return nil
+	}
+	switch c := cmp.Compare(elem, n.elem); {
+	case c < 0:
+		n.left = remove(n.left, elem, count, deleted)
+	case c > 0:
+		n.right = remove(n.right, elem, count, deleted)
+	default:
+		if n.count > count {
+			n.count -= count
+			*deleted = count
+			break
+		}
+		*deleted = n.count
+		// Rotate the node down until it has at most one child,
+		// then replace it with that child.
+		return removeNode(n)
+	}
+	n.fix()
+	return n
+}
+
+func removeNode[T cmp.Ordered](n *node[T]) *node[T] {
+	switch {
+	case n.left == nil:
+		return n.right
+	case n.right == nil:
+		return n.left
+	case n.left.priority > n.right.priority:
+		n = rotateRight(n)
+		n.right = removeNode(n.right)
+	default:
+		n = rotateLeft(n)
+		n.left = removeNode(n.left)
+	}
+	n.fix()
+	return n
+}
+
+// Rank returns the number of elements in the multiset that are
+// strictly smaller than elem, counting every copy.
+func (m *Multiset[T]) Rank(elem T) int {
+	rank := 0
+	n := m.root
+	for n != nil {
+		switch c := cmp.Compare(elem, n.elem); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			rank += n.left.subtreeSize() + n.count
+			n = n.right
+		default:
+			return rank + n.left.subtreeSize()
+		}
+	}
+	return rank
+}
+
+// Select returns the k-th smallest element of the multiset,
+// counting every copy and counting from 0, so that Select(0) is the
+// smallest element and Select(Len()-1) is the largest. It returns
+// false if k is out of range.
+func (m *Multiset[T]) Select(k int) (T, bool) {
+	if k < 0 || k >= m.Len() {
+		var zero T
+		return zero, false
+	}
+	n := m.root
+	for {
+		left := n.left.subtreeSize()
+		switch {
+		case k < left:
+			n = n.left
+		case k < left+n.count:
+			return n.elem, true
+		default:
+			k -= left + n.count
+			n = n.right
+		}
+	}
+}
+
+// Floor returns the largest element of the multiset that is <= elem.
+// It returns false if there is no such element.
+func (m *Multiset[T]) Floor(elem T) (T, bool) {
+	var found *node[T]
+	n := m.root
+	for n != nil {
+		switch c := cmp.Compare(elem, n.elem); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			found = n
+			n = n.right
+		default:
+			return n.elem, true
+		}
+	}
+	return elemOf(found)
+}
+
+// Ceiling returns the smallest element of the multiset that is >= elem.
+// It returns false if there is no such element.
+func (m *Multiset[T]) Ceiling(elem T) (T, bool) {
+	var found *node[T]
+	n := m.root
+	for n != nil {
+		switch c := cmp.Compare(elem, n.elem); {
+		case c < 0:
+			found = n
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.elem, true
+		}
+	}
+	return elemOf(found)
+}
+
+func elemOf[T cmp.Ordered](n *node[T]) (T, bool) {
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+	return n.elem, true
+}
+
+// Min returns the smallest element of the multiset. It returns
+// false if the multiset is empty.
+func (m *Multiset[T]) Min() (T, bool) {
+	return m.Select(0)
+}
+
+// Max returns the largest element of the multiset. It returns
+// false if the multiset is empty.
+func (m *Multiset[T]) Max() (T, bool) {
+	return m.Select(m.Len() - 1)
+}
+
+// Iter iterates through every element of the multiset in ascending
+// order and calls function f using the element as an argument for T,
+// once for every copy of the element.
+func (m *Multiset[T]) Iter(f func(elem T)) {
+	for elem, count := range m.All() {
+		for i := 0; i < count; i++ {
+			f(elem)
+		}
+	}
+}
+
+// All returns an iterator over the distinct elements of the
+// multiset in ascending order, along with how many copies of
+// each the multiset holds.
+func (m *Multiset[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		walk(m.root, yield)
+	}
+}
+
+func walk[T cmp.Ordered](n *node[T], yield func(T, int) bool) bool {
+	if n == nil {
+		return true
+	}
+	return walk(n.left, yield) && yield(n.elem, n.count) && walk(n.right, yield)
+}
@@ -0,0 +1,131 @@
+package multiset
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/manniwood/mmmdatastructures/v3/bag"
+)
+
+func TestInt(t *testing.T) {
+	m := New[int]()
+	for _, x := range []int{5, 1, 3, 3, 9, 7, 3} {
+		m.Insert(x)
+	}
+	if m.Len() != 7 {
+		t.Errorf("Expected length 7, got %v", m.Len())
+	}
+	if got := m.Count(3); got != 3 {
+		t.Errorf("Expected 3 copies of 3, got %v", got)
+	}
+	var tests = []struct {
+		x       int
+		rank    int
+		floor   int
+		ceiling int
+	}{
+		{0, 0, -1, 1},
+		{1, 0, 1, 1},
+		{3, 1, 3, 3},
+		{4, 4, 3, 5},
+		{9, 6, 9, 9},
+		{10, 7, 9, -1},
+	}
+	for _, test := range tests {
+		if got := m.Rank(test.x); got != test.rank {
+			t.Errorf("Expected rank of %v to be %v, got %v", test.x, test.rank, got)
+		}
+		if got, ok := m.Floor(test.x); ok != (test.floor >= 0) || ok && got != test.floor {
+			t.Errorf("Expected floor of %v to be %v, got %v %v", test.x, test.floor, got, ok)
+		}
+		if got, ok := m.Ceiling(test.x); ok != (test.ceiling >= 0) || ok && got != test.ceiling {
+			t.Errorf("Expected ceiling of %v to be %v, got %v %v", test.x, test.ceiling, got, ok)
+		}
+	}
+	for k, want := range []int{1, 3, 3, 3, 5, 7, 9} {
+		if got, ok := m.Select(k); !ok || got != want {
+			t.Errorf("Expected select %v to be %v, got %v", k, want, got)
+		}
+	}
+	if _, ok := m.Select(7); ok {
+		t.Error("Expected select past the end to fail")
+	}
+	if !m.Delete(3) || m.Count(3) != 2 {
+		t.Errorf("Expected 2 copies of 3 after Delete, got %v", m.Count(3))
+	}
+	if m.Delete(4) {
+		t.Error("Did not expect to delete 4")
+	}
+	if got := m.DeleteN(3, 5); got != 2 || m.Has(3) {
+		t.Errorf("Expected to delete the last 2 copies of 3, deleted %v", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	b := bag.New[string]()
+	b.PutSlice([]string{"pear", "apple", "fig", "apple"})
+	m := FromBag(b)
+	var got []string
+	m.Iter(func(elem string) {
+		got = append(got, elem)
+	})
+	want := []string{"apple", "apple", "fig", "pear"}
+	if !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if min, _ := m.Min(); min != "apple" {
+		t.Errorf("Expected min apple, got %v", min)
+	}
+	if max, _ := m.Max(); max != "pear" {
+		t.Errorf("Expected max pear, got %v", max)
+	}
+	for elem, count := range m.All() {
+		if count != b[elem] {
+			t.Errorf("Expected %v copies of %v, got %v", b[elem], elem, count)
+		}
+	}
+}
+
+// TestRandom checks every operation against a sorted slice.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := New[int]()
+	var sorted []int
+	for i := 0; i < 5000; i++ {
+		x := r.Intn(200)
+		if r.Intn(3) > 0 {
+			m.Insert(x)
+			j, _ := slices.BinarySearch(sorted, x)
+			sorted = slices.Insert(sorted, j, x)
+		} else {
+			j, found := slices.BinarySearch(sorted, x)
+			if m.Delete(x) != found {
+				t.Fatalf("Expected Delete(%v) to return %v", x, found)
+			}
+			if found {
+				sorted = slices.Delete(sorted, j, j+1)
+			}
+		}
+		rank, _ := slices.BinarySearch(sorted, x)
+		if got := m.Rank(x); got != rank {
+			t.Fatalf("Expected rank of %v to be %v, got %v", x, rank, got)
+		}
+		if m.Len() != len(sorted) {
+			t.Fatalf("Expected length %v, got %v", len(sorted), m.Len())
+		}
+		if len(sorted) > 0 {
+			k := r.Intn(len(sorted))
+			if got, _ := m.Select(k); got != sorted[k] {
+				t.Fatalf("Expected select %v to be %v, got %v", k, sorted[k], got)
+			}
+		}
+	}
+	var got []int
+	m.Iter(func(elem int) {
+		got = append(got, elem)
+	})
+	if !slices.Equal(sorted, got) {
+		t.Error("Elements were not as expected")
+	}
+}
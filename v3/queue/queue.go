@@ -31,7 +31,7 @@
 package queue
 
 import (
-	"constraints"
+	"cmp"
 	"errors"
 	"fmt"
 )
@@ -60,7 +60,7 @@ var QueueCapacityExceeded = errors.New("Queue Capacity Exceeded")
 var QueueEmpty = errors.New("Queue Empty")
 
 // Queue holds the data and state of the queue.
-type Queue[T constraints.Ordered] struct {
+type Queue[T cmp.Ordered] struct {
 	data     []T
 	head     int
 	tail     int
@@ -69,12 +69,12 @@ type Queue[T constraints.Ordered] struct {
 }
 
 // New returns a new empty queue of the default capacity.
-func New[T constraints.Ordered]() (*Queue[T], error) {
+func New[T cmp.Ordered]() (*Queue[T], error) {
 	return NewWithCapacity[T](DefaultCapacity)
 }
 
 // NewWithCapacity returns a new empty queue with the requested capacity.
-func NewWithCapacity[T constraints.Ordered](capacity int) (*Queue[T], error) {
+func NewWithCapacity[T cmp.Ordered](capacity int) (*Queue[T], error) {
 	if capacity < 1 {
 		return nil, &NegativeQueueCapacityError{
 			msg: fmt.Sprintf("capacity %d is zero or negative", capacity),
@@ -0,0 +1,254 @@
+// Package segtree implements a segment tree with lazy range updates.
+//
+// A segment tree holds a fixed-length array of items along with
+// the aggregate of every one of a set of O(n) ranges of them, such
+// that the aggregate of any range of items can be found, and an
+// update can be applied to every item in a range, in O(log n) time.
+// The aggregate is whatever an associative Combine function makes
+// it: a sum, a minimum, a maximum, and so on.
+//
+// Updates to a range are applied lazily: the aggregates of the
+// ranges that cover the updated range are updated at once, and the
+// update is only pushed down to smaller ranges when a later query
+// or update needs them.
+package segtree
+
+import (
+	"errors"
+	"fmt"
+)
+
+var MissingCombine = errors.New("Missing Combine")
+var IncompleteUpdate = errors.New("Incomplete Update: Apply and Compose Must Both Be Set")
+
+// Ops tells the tree how to aggregate items of type T and how to
+// apply updates of type U to them.
+type Ops[T, U any] struct {
+	// Combine returns the aggregate of two adjacent runs of items,
+	// given the aggregates of each. It must be associative.
+	Combine func(a, b T) T
+	// Apply returns the aggregate of a run of n items after update
+	// u has been applied to each of them, given their aggregate x.
+	Apply func(u U, x T, n int) T
+	// Compose returns the single update that has the same effect
+	// as applying older and then newer.
+	Compose func(newer, older U) U
+}
+
+// Tree holds the items and aggregates of the segment tree. Node 1
+// covers every item, and the children of node i are 2i and 2i+1,
+// which cover the left and right halves of the items node i covers.
+// pending[i] tells whether lazy[i] holds an update that has been
+// applied to node i but not yet to its children.
+type Tree[T, U any] struct {
+	ops     Ops[T, U]
+	n       int
+	agg     []T
+	lazy    []U
+	pending []bool
+}
+
+// New returns a new tree holding the items of data, aggregated using
+// ops. Combine must be set. Apply and Compose need only be set if
+// Update is going to be used, but one may not be set without the other.
+func New[T, U any](data []T, ops Ops[T, U]) (*Tree[T, U], error) {
+	if ops.Combine == nil {
+		return nil, MissingCombine
+	}
+	if (ops.Apply == nil) != (ops.Compose == nil) {
+		return nil, IncompleteUpdate
+	}
+	n := len(data)
+	t := &Tree[T, U]{
+		ops: ops,
+		n:   n,
+		agg: make([]T, 4*n),
+	}
+	if ops.Apply != nil {
+		t.lazy = make([]U, 4*n)
+		t.pending = make([]bool, 4*n)
+	}
+	if n > 0 {
+		t.build(1, 0, n, data)
+	}
+	return t, nil
+}
+
+func (t *Tree[T, U]) build(node int, lo int, hi int, data []T) {
+	if hi-lo == 1 {
+		t.agg[node] = data[lo]
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.build(2*node, lo, mid, data)
+	t.build(2*node+1, mid, hi, data)
+	t.agg[node] = t.ops.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+// Len returns the number of items in the tree.
+func (t *Tree[T, U]) Len() int {
+	return t.n
+}
+
+func (t *Tree[T, U]) checkRange(lo int, hi int) {
+	if lo < 0 || lo > hi || hi > t.n {
+		panic(fmt.Sprintf("segtree: range [%d, %d) out of range [0, %d)", lo, hi, t.n))
+	}
+}
+
+// applyNode applies u to every item covered by node, which covers n items.
+func (t *Tree[T, U]) applyNode(node int, u U, n int) {
+	t.agg[node] = t.ops.Apply(u, t.agg[node], n)
+	if n == 1 {
+		return
+	}
+	if t.pending[node] {
+		t.lazy[node] = t.ops.Compose(u, t.lazy[node])
+	} else {
+		t.lazy[node] = u
+		t.pending[node] = true
+	}
+}
+
+// push passes the pending update of node, which covers [lo, hi),
+// down to its children.
+func (t *Tree[T, U]) push(node int, lo int, mid int, hi int) {
+	if t.pending == nil || !t.pending[node] {
+		return
+	}
+	t.applyNode(2*node, t.lazy[node], mid-lo)
+	t.applyNode(2*node+1, t.lazy[node], hi-mid)
+	var zero U
+	t.lazy[node] = zero
+	t.pending[node] = false
+}
+
+// Query returns the aggregate of the items in the half-open range
+// [lo, hi). It returns false if the range is empty. It panics unless
+// 0 <= lo <= hi <= Len().
+func (t *Tree[T, U]) Query(lo int, hi int) (T, bool) {
+	t.checkRange(lo, hi)
+	if lo == hi {
+		var zero T
+		return zero, false
+	}
+	return t.query(1, 0, t.n, lo, hi), true
+}
+
+// query returns the aggregate of [qlo, qhi), which must overlap
+// [lo, hi), the range covered by node.
+func (t *Tree[T, U]) query(node int, lo int, hi int, qlo int, qhi int) T {
+	if qlo <= lo && hi <= qhi {
+		return t.agg[node]
+	}
+	mid := lo + (hi-lo)/2
+	t.push(node, lo, mid, hi)
+	switch {
+	case qhi <= mid:
+		return t.query(2*node, lo, mid, qlo, qhi)
+	case qlo >= mid:
+		return t.query(2*node+1, mid, hi, qlo, qhi)
+	}
+	return t.ops.Combine(
+		t.query(2*node, lo, mid, qlo, qhi),
+		t.query(2*node+1, mid, hi, qlo, qhi))
+}
+
+// Update applies update u to every item in the half-open range
+// [lo, hi). It panics unless 0 <= lo <= hi <= Len(), or if the
+// tree was made without Apply and Compose.
+func (t *Tree[T, U]) Update(lo int, hi int, u U) {
+	if t.ops.Apply == nil {
+		panic("segtree: Update needs Apply and Compose")
+	}
+	t.checkRange(lo, hi)
+	if lo < hi {
+		t.update(1, 0, t.n, lo, hi, u)
+	}
+}
+
+func (t *Tree[T, U]) update(node int, lo int, hi int, qlo int, qhi int, u U) {
+	if qhi <= lo || hi <= qlo {
+		return
+	}
+	if qlo <= lo && hi <= qhi {
+		t.applyNode(node, u, hi-lo)
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.push(node, lo, mid, hi)
+	t.update(2*node, lo, mid, qlo, qhi, u)
+	t.update(2*node+1, mid, hi, qlo, qhi, u)
+	t.agg[node] = t.ops.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+// Get returns item i. It panics if i is out of range.
+func (t *Tree[T, U]) Get(i int) T {
+	t.checkIndex(i)
+	x, _ := t.Query(i, i+1)
+	return x
+}
+
+// Set replaces item i with x. It panics if i is out of range.
+func (t *Tree[T, U]) Set(i int, x T) {
+	t.checkIndex(i)
+	t.set(1, 0, t.n, i, x)
+}
+
+func (t *Tree[T, U]) checkIndex(i int) {
+	if i < 0 || i >= t.n {
+		panic(fmt.Sprintf("segtree: index %d out of range [0, %d)", i, t.n))
+	}
+}
+
+func (t *Tree[T, U]) set(node int, lo int, hi int, i int, x T) {
+	if hi-lo == 1 {
+		t.agg[node] = x
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.push(node, lo, mid, hi)
+	if i < mid {
+		t.set(2*node, lo, mid, i, x)
+	} else {
+		t.set(2*node+1, mid, hi, i, x)
+	}
+	t.agg[node] = t.ops.Combine(t.agg[2*node], t.agg[2*node+1])
+}
+
+// Number is the set of types that the ready-made Ops work on.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumAdd returns Ops that aggregate items by summing them, and
+// update them by adding to them.
+func SumAdd[T Number]() Ops[T, T] {
+	return Ops[T, T]{
+		Combine: func(a, b T) T { return a + b },
+		Apply:   func(u T, x T, n int) T { return x + u*T(n) },
+		Compose: func(newer, older T) T { return newer + older },
+	}
+}
+
+// MinAdd returns Ops that aggregate items by taking their minimum,
+// and update them by adding to them.
+func MinAdd[T Number]() Ops[T, T] {
+	return Ops[T, T]{
+		Combine: func(a, b T) T { return min(a, b) },
+		Apply:   func(u T, x T, n int) T { return x + u },
+		Compose: func(newer, older T) T { return newer + older },
+	}
+}
+
+// MaxAdd returns Ops that aggregate items by taking their maximum,
+// and update them by adding to them.
+func MaxAdd[T Number]() Ops[T, T] {
+	return Ops[T, T]{
+		Combine: func(a, b T) T { return max(a, b) },
+		Apply:   func(u T, x T, n int) T { return x + u },
+		Compose: func(newer, older T) T { return newer + older },
+	}
+}
@@ -0,0 +1,172 @@
+package set
+
+import "iter"
+
+// OrderedSet is a set that remembers the order in which elements
+// were first put into it, so that iterating over it is repeatable,
+// unlike iterating over Set.
+//
+// It is a map from each element to a node in a doubly-linked list,
+// so Put, Delete, Has, MoveToFront and MoveToBack are all O(1).
+type OrderedSet[T comparable] struct {
+	nodes map[T]*orderedNode[T]
+	// root is a sentinel: root.next is the first node, and
+	// root.prev is the last node.
+	root orderedNode[T]
+	// iterating is the number of iterations over the set that
+	// are under way.
+	iterating int
+}
+
+type orderedNode[T comparable] struct {
+	elem T
+	prev *orderedNode[T]
+	next *orderedNode[T]
+}
+
+func NewOrdered[T comparable]() *OrderedSet[T] {
+	s := &OrderedSet[T]{
+		nodes: make(map[T]*orderedNode[T]),
+	}
+	s.root.next = &s.root
+	s.root.prev = &s.root
+	return s
+}
+
+func (s *OrderedSet[T]) Has(elem T) bool {
+	_, ok := s.nodes[elem]
+	return ok
+}
+
+// Put puts an element at the back of the set. If the element
+// is already in the set, it keeps its current position.
+func (s *OrderedSet[T]) Put(elem T) {
+	if _, ok := s.nodes[elem]; ok {
+		return
+	}
+	n := &orderedNode[T]{elem: elem}
+	s.insertAfter(n, s.root.prev)
+	s.nodes[elem] = n
+}
+
+func (s *OrderedSet[T]) Delete(elem T) {
+	n, ok := s.nodes[elem]
+	if !ok {
+		return
+	}
+	s.unlink(n)
+	delete(s.nodes, elem)
+}
+
+func (s *OrderedSet[T]) PutSlice(elements []T) {
+	for _, elem := range elements {
+		s.Put(elem)
+	}
+}
+
+// Len returns the number of elements in the set.
+func (s *OrderedSet[T]) Len() int {
+	return len(s.nodes)
+}
+
+// MoveToFront moves an element to the front of the set.
+// It returns false if the element is not in the set.
+func (s *OrderedSet[T]) MoveToFront(elem T) bool {
+	n, ok := s.nodes[elem]
+	if !ok {
+		return false
+	}
+	s.move(n, true)
+	return true
+}
+
+// MoveToBack moves an element to the back of the set.
+// It returns false if the element is not in the set.
+func (s *OrderedSet[T]) MoveToBack(elem T) bool {
+	n, ok := s.nodes[elem]
+	if !ok {
+		return false
+	}
+	s.move(n, false)
+	return true
+}
+
+// Iter iterates through every element of the set in order
+// and calls function f using the element as an argument for T.
+// As with ranging over a map, f may delete elements from the set;
+// deleted elements that have not been reached are not visited.
+// f may also move elements with MoveToFront and MoveToBack. A moved
+// element is visited at its new place if the iteration has yet to
+// reach that place, so may be visited twice or not at all, but
+// every other element is still visited exactly once.
+func (s *OrderedSet[T]) Iter(f func(elem T)) {
+	s.iterating++
+	defer func() { s.iterating-- }()
+	for n := s.root.next; n != &s.root; {
+		next := n.next
+		f(n.elem)
+		n = s.live(next)
+	}
+}
+
+// All returns an iterator over every element of the set in order.
+// Elements may be deleted or moved during iteration, as for Iter.
+func (s *OrderedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.iterating++
+		defer func() { s.iterating-- }()
+		for n := s.root.next; n != &s.root; {
+			next := n.next
+			if !yield(n.elem) {
+				return
+			}
+			n = s.live(next)
+		}
+	}
+}
+
+// move moves n to the front of the list, or to the back. While the
+// set is being iterated over, n is unlinked as if deleted, so that
+// an iteration about to step from n still finds its way on, and a
+// new node takes its place; this way the iteration neither skips
+// nor repeats the elements that the move jumps over.
+func (s *OrderedSet[T]) move(n *orderedNode[T], toFront bool) {
+	s.unlink(n)
+	if s.iterating > 0 {
+		moved := *n
+		n = &moved
+		s.nodes[n.elem] = n
+	}
+	if toFront {
+		s.insertAfter(n, &s.root)
+	} else {
+		s.insertAfter(n, s.root.prev)
+	}
+}
+
+func (s *OrderedSet[T]) insertAfter(n, at *orderedNode[T]) {
+	n.prev = at
+	n.next = at.next
+	at.next.prev = n
+	at.next = n
+}
+
+// unlink takes n out of the list. It leaves n.next alone, so that an
+// iteration that is about to step from n to n.next can still find
+// its way back into the list; n.prev being nil marks n as unlinked.
+func (s *OrderedSet[T]) unlink(n *orderedNode[T]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev = nil
+}
+
+// live returns n, or if n has been unlinked, the first node still in
+// the list that followed it. Every unlinked node points to a node
+// that was still in the list when it was unlinked, so this always
+// ends at a node in the list or at root.
+func (s *OrderedSet[T]) live(n *orderedNode[T]) *orderedNode[T] {
+	for n != &s.root && n.prev == nil {
+		n = n.next
+	}
+	return n
+}
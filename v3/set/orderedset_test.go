@@ -0,0 +1,141 @@
+package set
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func orderedElems[T comparable](s *OrderedSet[T]) []T {
+	got := []T{}
+	s.Iter(func(elem T) {
+		got = append(got, elem)
+	})
+	return got
+}
+
+func TestOrdered(t *testing.T) {
+	s := NewOrdered[string]()
+	s.PutSlice([]string{"c", "a", "b"})
+	s.Put("a")
+	s.Put("d")
+	want := []string{"c", "a", "b", "d"}
+	if got := orderedElems(s); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+	if got := slices.Collect(s.All()); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+	if !s.Has("b") {
+		t.Errorf("should have b")
+	}
+
+	s.Delete("a")
+	s.Delete("z")
+	if s.Has("a") {
+		t.Errorf("should NOT have a after deletion")
+	}
+	s.Put("a")
+	want = []string{"c", "b", "d", "a"}
+	if got := orderedElems(s); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+	if s.Len() != 4 {
+		t.Errorf("expected length 4, got %v", s.Len())
+	}
+}
+
+func TestOrderedMove(t *testing.T) {
+	s := NewOrdered[int]()
+	s.PutSlice([]int{1, 2, 3, 4})
+	s.MoveToFront(3)
+	s.MoveToBack(1)
+	if s.MoveToFront(5) {
+		t.Errorf("should not be able to move an element that is not in the set")
+	}
+	want := []int{3, 2, 4, 1}
+	if got := orderedElems(s); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+}
+
+func TestOrderedDeleteDuringIteration(t *testing.T) {
+	s := NewOrdered[int]()
+	s.PutSlice([]int{1, 2, 3, 4, 5, 6})
+	got := []int{}
+	for elem := range s.All() {
+		got = append(got, elem)
+		// Delete the current element, and, from 3 on, the next one
+		// too, which should then not be visited.
+		s.Delete(elem)
+		if elem >= 3 {
+			s.Delete(elem + 1)
+		}
+	}
+	if want := []int{1, 2, 3, 5}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+	if s.Len() != 0 {
+		t.Errorf("expected length 0, got %v", s.Len())
+	}
+
+	s.PutSlice([]int{1, 2, 3, 4})
+	got = []int{}
+	s.Iter(func(elem int) {
+		got = append(got, elem)
+		s.Delete(elem)
+		s.Delete(elem + 1)
+	})
+	if want := []int{1, 3}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+}
+
+func TestOrderedMoveDuringIteration(t *testing.T) {
+	s := NewOrdered[int]()
+	s.PutSlice([]int{1, 2, 3, 4, 5, 6})
+	got := []int{}
+	for elem := range s.All() {
+		got = append(got, elem)
+		switch elem {
+		case 1:
+			// 2 has yet to be visited, so it is visited at the
+			// back, once 3 to 6 have been.
+			s.MoveToBack(2)
+		case 3:
+			// 4 has yet to be visited, and moving it to the front
+			// means it never will be, but 5 and 6 still are. 1
+			// has been visited, and moving it to the back means
+			// it is visited again.
+			s.MoveToFront(4)
+			s.MoveToBack(1)
+		}
+	}
+	if want := []int{1, 3, 5, 6, 2, 1}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+	// Visiting 1 again moved 2 to the back again.
+	if want := []int{4, 3, 5, 6, 1, 2}; !reflect.DeepEqual(want, orderedElems(s)) {
+		t.Errorf("expected want %#v to equal got %#v", want, orderedElems(s))
+	}
+
+	// Moving the next element, which the iteration has already
+	// stepped to, must not make it jump over the rest.
+	s = NewOrdered[int]()
+	s.PutSlice([]int{1, 2, 3, 4})
+	got = []int{}
+	s.Iter(func(elem int) {
+		got = append(got, elem)
+		if elem == 1 {
+			s.MoveToBack(2)
+		}
+	})
+	if want := []int{1, 3, 4, 2}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected want %#v to equal got %#v", want, got)
+	}
+	s.Delete(3)
+	s.MoveToFront(2)
+	if want := []int{2, 1, 4}; !reflect.DeepEqual(want, orderedElems(s)) {
+		t.Errorf("expected want %#v to equal got %#v", want, orderedElems(s))
+	}
+}
@@ -0,0 +1,254 @@
+// Package sorting implements general-purpose in-place sorting
+// and selection algorithms.
+//
+// Unlike maxheap.Sort, which treats index 0 of its argument as
+// unused, everything in this package works on the whole slice.
+//
+// Every function comes in two flavours: one for cmp.Ordered types,
+// and a Func variant that takes a comparison function, which must
+// return a negative number when a < b, a positive number when a > b,
+// and zero when a == b.
+package sorting
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// insertionSortThreshold is the size at or below which IntroSort
+// and NthElement stop partitioning and fall back to insertion sort.
+const insertionSortThreshold = 12
+
+// HeapSort sorts data in ascending order using heap sort.
+// It runs in O(n log n) time, uses no extra space, and is not stable.
+func HeapSort[T cmp.Ordered](data []T) {
+	HeapSortFunc(data, cmp.Compare[T])
+}
+
+// HeapSortFunc is like HeapSort, but orders elements using cmp.
+func HeapSortFunc[T any](data []T, cmp func(a, b T) int) {
+	heapSort(data, 0, len(data), cmp)
+}
+
+// heapSort sorts data[lo:hi]. The heap is laid out over data[lo:hi]
+// with the children of i at 2i+1 and 2i+2, counting from lo.
+func heapSort[T any](data []T, lo, hi int, cmp func(a, b T) int) {
+	size := hi - lo
+	// Turn into a max heap
+	for i := size/2 - 1; i >= 0; i-- {
+		siftDown(data, lo, i, size, cmp)
+	}
+	// Move max val to the end of the range and then re-heapify all of the range
+	// except for the max at the end, and so on.
+	for size > 1 {
+		size--
+		data[lo], data[lo+size] = data[lo+size], data[lo]
+		siftDown(data, lo, 0, size, cmp)
+	}
+}
+
+// siftDown sinks the element at parent down to its correct level in the
+// max heap of the given size that starts at data[lo].
+func siftDown[T any](data []T, lo, parent, size int, cmp func(a, b T) int) {
+	for {
+		child := 2*parent + 1
+		if child >= size {
+			return
+		}
+		// Make child the index of the larger of the parent's two children.
+		if child+1 < size && cmp(data[lo+child+1], data[lo+child]) > 0 {
+			child++
+		}
+		if cmp(data[lo+parent], data[lo+child]) >= 0 {
+			return
+		}
+		data[lo+parent], data[lo+child] = data[lo+child], data[lo+parent]
+		parent = child
+	}
+}
+
+// PartialSort rearranges data so that data[:k] holds the k smallest
+// elements in ascending order. The order of data[k:] is unspecified.
+// If k is larger than len(data), the whole slice is sorted.
+// It runs in O(n log k) time.
+func PartialSort[T cmp.Ordered](data []T, k int) {
+	PartialSortFunc(data, k, cmp.Compare[T])
+}
+
+// PartialSortFunc is like PartialSort, but orders elements using cmp.
+func PartialSortFunc[T any](data []T, k int, cmp func(a, b T) int) {
+	if k <= 0 {
+		return
+	}
+	if k >= len(data) {
+		heapSort(data, 0, len(data), cmp)
+		return
+	}
+	// Keep the k smallest elements seen so far in a max heap over
+	// data[:k]. Anything smaller than the top of the heap displaces it.
+	for i := k/2 - 1; i >= 0; i-- {
+		siftDown(data, 0, i, k, cmp)
+	}
+	for i := k; i < len(data); i++ {
+		if cmp(data[i], data[0]) < 0 {
+			data[i], data[0] = data[0], data[i]
+			siftDown(data, 0, 0, k, cmp)
+		}
+	}
+	// The heap already holds the right elements; finish the heap sort.
+	for size := k - 1; size > 0; size-- {
+		data[0], data[size] = data[size], data[0]
+		siftDown(data, 0, 0, size, cmp)
+	}
+}
+
+// NthElement rearranges data so that data[n] is the element that
+// would be there if data were sorted, every element of data[:n] is
+// less than or equal to it, and every element of data[n+1:] is
+// greater than or equal to it. This is quickselect: it runs in
+// O(n) time on average, and falls back to heap sort to guarantee
+// O(n log n) in the worst case. It panics if n is out of range.
+func NthElement[T cmp.Ordered](data []T, n int) {
+	NthElementFunc(data, n, cmp.Compare[T])
+}
+
+// NthElementFunc is like NthElement, but orders elements using cmp.
+func NthElementFunc[T any](data []T, n int, cmp func(a, b T) int) {
+	if n < 0 || n >= len(data) {
+		panic("sorting: NthElement index out of range")
+	}
+	lo, hi := 0, len(data)
+	depth := depthLimit(len(data))
+	for hi-lo > insertionSortThreshold {
+		if depth == 0 {
+			heapSort(data, lo, hi, cmp)
+			return
+		}
+		depth--
+		lt, gt := partition(data, lo, hi, cmp)
+		switch {
+		case n < lt:
+			hi = lt
+		case n >= gt:
+			lo = gt
+		default:
+			// data[n] is equal to the pivot, which is already in place.
+			return
+		}
+	}
+	insertionSort(data, lo, hi, cmp)
+}
+
+// IntroSort sorts data in ascending order using introsort: quicksort
+// that switches to heap sort when the recursion gets too deep, and to
+// insertion sort for small ranges. It runs in O(n log n) time and
+// is not stable.
+func IntroSort[T cmp.Ordered](data []T) {
+	IntroSortFunc(data, cmp.Compare[T])
+}
+
+// IntroSortFunc is like IntroSort, but orders elements using cmp.
+func IntroSortFunc[T any](data []T, cmp func(a, b T) int) {
+	introSort(data, 0, len(data), depthLimit(len(data)), cmp)
+}
+
+func introSort[T any](data []T, lo, hi, depth int, cmp func(a, b T) int) {
+	for hi-lo > insertionSortThreshold {
+		if depth == 0 {
+			heapSort(data, lo, hi, cmp)
+			return
+		}
+		depth--
+		lt, gt := partition(data, lo, hi, cmp)
+		// Recurse into the smaller side and loop on the larger one,
+		// so that the stack never gets deeper than O(log n).
+		if lt-lo < hi-gt {
+			introSort(data, lo, lt, depth, cmp)
+			lo = gt
+		} else {
+			introSort(data, gt, hi, depth, cmp)
+			hi = lt
+		}
+	}
+	insertionSort(data, lo, hi, cmp)
+}
+
+// depthLimit returns how many levels of partitioning IntroSort and
+// NthElement allow before falling back to heap sort.
+func depthLimit(n int) int {
+	return 2 * bits.Len(uint(n))
+}
+
+// partition does a three-way partition of data[lo:hi] around a
+// median-of-three pivot. Afterwards, data[lo:lt] is less than the
+// pivot, data[lt:gt] is equal to it, and data[gt:hi] is greater.
+// Grouping the equal elements keeps slices with many duplicates
+// from degrading to quadratic time.
+func partition[T any](data []T, lo, hi int, cmp func(a, b T) int) (lt, gt int) {
+	medianOfThree(data, lo, lo+(hi-lo)/2, hi-1, cmp)
+	pivot := data[lo+(hi-lo)/2]
+	lt, i, gt := lo, lo, hi
+	for i < gt {
+		c := cmp(data[i], pivot)
+		switch {
+		case c < 0:
+			data[lt], data[i] = data[i], data[lt]
+			lt++
+			i++
+		case c > 0:
+			gt--
+			data[i], data[gt] = data[gt], data[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// medianOfThree orders data[a], data[b] and data[c] so that
+// data[b] holds the median of the three.
+func medianOfThree[T any](data []T, a, b, c int, cmp func(a, b T) int) {
+	if cmp(data[b], data[a]) < 0 {
+		data[a], data[b] = data[b], data[a]
+	}
+	if cmp(data[c], data[b]) < 0 {
+		data[b], data[c] = data[c], data[b]
+		if cmp(data[b], data[a]) < 0 {
+			data[a], data[b] = data[b], data[a]
+		}
+	}
+}
+
+func insertionSort[T any](data []T, lo, hi int, cmp func(a, b T) int) {
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && cmp(data[j], data[j-1]) < 0; j-- {
+			data[j], data[j-1] = data[j-1], data[j]
+		}
+	}
+}
+
+// IsSorted reports whether data is sorted in ascending order.
+func IsSorted[T cmp.Ordered](data []T) bool {
+	return IsSortedUntil(data) == len(data)
+}
+
+// IsSortedFunc is like IsSorted, but orders elements using cmp.
+func IsSortedFunc[T any](data []T, cmp func(a, b T) int) bool {
+	return IsSortedUntilFunc(data, cmp) == len(data)
+}
+
+// IsSortedUntil returns the length of the longest prefix of data
+// that is sorted in ascending order.
+func IsSortedUntil[T cmp.Ordered](data []T) int {
+	return IsSortedUntilFunc(data, cmp.Compare[T])
+}
+
+// IsSortedUntilFunc is like IsSortedUntil, but orders elements using cmp.
+func IsSortedUntilFunc[T any](data []T, cmp func(a, b T) int) int {
+	for i := 1; i < len(data); i++ {
+		if cmp(data[i], data[i-1]) < 0 {
+			return i
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,172 @@
+package sorting
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// inputs returns a variety of int slices to sort: empty, tiny,
+// random, already sorted, reversed, and full of duplicates.
+func inputs() [][]int {
+	r := rand.New(rand.NewSource(1))
+	random := func(n, max int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = r.Intn(max)
+		}
+		return s
+	}
+	ascending := make([]int, 500)
+	descending := make([]int, 500)
+	for i := range ascending {
+		ascending[i] = i
+		descending[i] = 500 - i
+	}
+	return [][]int{
+		nil,
+		{},
+		{1},
+		{2, 1},
+		{3, 1, 2},
+		random(10, 5),
+		random(100, 1000),
+		random(1000, 1000000),
+		random(1000, 3),
+		ascending,
+		descending,
+	}
+}
+
+func TestHeapSort(t *testing.T) {
+	for _, input := range inputs() {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		got := slices.Clone(input)
+		HeapSort(got)
+		if !slices.Equal(want, got) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestIntroSort(t *testing.T) {
+	for _, input := range inputs() {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		got := slices.Clone(input)
+		IntroSort(got)
+		if !slices.Equal(want, got) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPartialSort(t *testing.T) {
+	for _, input := range inputs() {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		for _, k := range []int{0, 1, 2, 7, len(input) / 2, len(input), len(input) + 1} {
+			got := slices.Clone(input)
+			PartialSort(got, k)
+			n := min(k, len(input))
+			if !slices.Equal(want[:n], got[:n]) {
+				t.Errorf("k=%v: expected prefix %v, got %v", k, want[:n], got[:n])
+			}
+			// Nothing should have been lost or made up.
+			slices.Sort(got)
+			if !slices.Equal(want, got) {
+				t.Errorf("k=%v: expected same elements %v, got %v", k, want, got)
+			}
+		}
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	for _, input := range inputs() {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		for n := 0; n < len(input); n += 1 + len(input)/20 {
+			got := slices.Clone(input)
+			NthElement(got, n)
+			if got[n] != want[n] {
+				t.Errorf("n=%v: expected %v, got %v", n, want[n], got[n])
+			}
+			for i := range got {
+				if i < n && got[i] > got[n] || i > n && got[i] < got[n] {
+					t.Errorf("n=%v: element %v at %v is on the wrong side", n, got[i], i)
+				}
+			}
+		}
+	}
+}
+
+func TestNthElementOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected NthElement to panic on an out of range index")
+		}
+	}()
+	NthElement([]int{1, 2, 3}, 3)
+}
+
+func TestFunc(t *testing.T) {
+	var input []string
+	for _, i := range inputs()[7] {
+		input = append(input, strconv.Itoa(i))
+	}
+	// Sort descending.
+	desc := func(a, b string) int {
+		return strings.Compare(b, a)
+	}
+	want := slices.Clone(input)
+	slices.SortFunc(want, desc)
+
+	got := slices.Clone(input)
+	HeapSortFunc(got, desc)
+	if !slices.Equal(want, got) {
+		t.Error("HeapSortFunc did not sort in descending order")
+	}
+	got = slices.Clone(input)
+	IntroSortFunc(got, desc)
+	if !slices.Equal(want, got) {
+		t.Error("IntroSortFunc did not sort in descending order")
+	}
+	got = slices.Clone(input)
+	PartialSortFunc(got, 10, desc)
+	if !slices.Equal(want[:10], got[:10]) {
+		t.Error("PartialSortFunc did not sort the prefix in descending order")
+	}
+	got = slices.Clone(input)
+	NthElementFunc(got, 10, desc)
+	if want[10] != got[10] {
+		t.Errorf("Expected NthElementFunc to find %v, got %v", want[10], got[10])
+	}
+	if !IsSortedFunc(want, desc) {
+		t.Error("Expected IsSortedFunc to be true")
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	var tests = []struct {
+		input []int
+		until int
+	}{
+		{nil, 0},
+		{[]int{1}, 1},
+		{[]int{1, 1, 2}, 3},
+		{[]int{1, 3, 2, 4}, 2},
+		{[]int{2, 1}, 1},
+	}
+	for _, test := range tests {
+		if got := IsSortedUntil(test.input); got != test.until {
+			t.Errorf("IsSortedUntil(%v): expected %v, got %v", test.input, test.until, got)
+		}
+		want := test.until == len(test.input)
+		if got := IsSorted(test.input); got != want {
+			t.Errorf("IsSorted(%v): expected %v, got %v", test.input, want, got)
+		}
+	}
+}
@@ -2,7 +2,7 @@
 package stack
 
 import (
-	"constraints"
+	"cmp"
 	"errors"
 	"fmt"
 )
@@ -31,7 +31,7 @@ var StackCapacityExceeded = errors.New("Stack Capacity Exceeded")
 var StackEmpty = errors.New("Stack Empty")
 
 // Stack holds the data and state of the stack.
-type Stack[T constraints.Ordered] struct {
+type Stack[T cmp.Ordered] struct {
 	data []T
 	// top is the topmost index of data[] that holds an element.
 	top      int
@@ -39,12 +39,12 @@ type Stack[T constraints.Ordered] struct {
 }
 
 // New returns a new empty stack of the default capacity.
-func New[T constraints.Ordered]() (*Stack[T], error) {
+func New[T cmp.Ordered]() (*Stack[T], error) {
 	return NewWithCapacity[T](DefaultCapacity)
 }
 
 // NewWithCapacity returns a new empty stack with the requested capacity.
-func NewWithCapacity[T constraints.Ordered](capacity int) (*Stack[T], error) {
+func NewWithCapacity[T cmp.Ordered](capacity int) (*Stack[T], error) {
 	if capacity < 1 {
 		return nil, &NegativeStackCapacityError{
 			msg: fmt.Sprintf("capacity %d is zero or negative", capacity),
@@ -0,0 +1,372 @@
+// Package zset implements a scored sorted set, modelled on a Redis
+// sorted set (ZSET).
+//
+// A ZSet maps each member to a score, like a map would, but also
+// keeps its members in order of score, so that it can tell you a
+// member's rank, iterate over the members with scores in a given
+// range, and pop the members with the lowest or highest scores.
+// Adding, removing and ranking members takes O(log n) time.
+//
+// Ranks count from 0, and RangeByRank and RangeByScore take
+// inclusive ranges, with negative ranks counting back from the
+// end, as in ZRANGE. Unlike Redis, which orders members with the
+// same score by member, a ZSet keeps them in the order they were
+// first added, since members need only be comparable, not ordered.
+//
+// Like a Redis sorted set, it is a map from members to skip list
+// nodes, plus a skip list ordered by score, in which each forward
+// link also records how many nodes it skips, so that ranks can be
+// counted on the way down.
+package zset
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+)
+
+// maxLevel is the most levels a skip list node can have, which is
+// plenty for 4^32 members.
+const maxLevel = 32
+
+// ZSet holds the members of the set and the skip list that orders them.
+type ZSet[M comparable, S cmp.Ordered] struct {
+	nodes map[M]*node[M, S]
+	// head is a sentinel whose links point to the first
+	// node at each level.
+	head  *node[M, S]
+	tail  *node[M, S]
+	level int
+	// seq numbers members in the order they were first added,
+	// which orders members with the same score.
+	seq uint64
+}
+
+type node[M comparable, S cmp.Ordered] struct {
+	member   M
+	score    S
+	seq      uint64
+	backward *node[M, S]
+	levels   []link[M, S]
+}
+
+// link points to the next node at some level, and records how
+// many nodes at level 0 it skips over, counting the one it
+// points to.
+type link[M comparable, S cmp.Ordered] struct {
+	forward *node[M, S]
+	span    int
+}
+
+// less tells you whether n comes before a node with the given
+// score and sequence number.
+func (n *node[M, S]) less(score S, seq uint64) bool {
+	if c := cmp.Compare(n.score, score); c != 0 {
+		return c < 0
+	}
+	return n.seq < seq
+}
+
+// New returns a new empty scored sorted set.
+func New[M comparable, S cmp.Ordered]() *ZSet[M, S] {
+	return &ZSet[M, S]{
+		nodes: make(map[M]*node[M, S]),
+		head:  &node[M, S]{levels: make([]link[M, S], maxLevel)},
+		level: 1,
+	}
+}
+
+// Len returns the number of members in the set.
+func (z *ZSet[M, S]) Len() int {
+	return len(z.nodes)
+}
+
+// Has tells you whether member is in the set.
+func (z *ZSet[M, S]) Has(member M) bool {
+	_, ok := z.nodes[member]
+	return ok
+}
+
+// Score returns the score of member, and whether it is in the set.
+func (z *ZSet[M, S]) Score(member M) (S, bool) {
+	n, ok := z.nodes[member]
+	if !ok {
+		var zero S
+		return zero, false
+	}
+	return n.score, true
+}
+
+// Add puts member in the set with the given score, or if it is
+// already in the set, changes its score. It returns true if member
+// was not already in the set.
+func (z *ZSet[M, S]) Add(member M, score S) bool {
+	n, ok := z.nodes[member]
+	if ok {
+		if n.score != score {
+			z.unlink(n)
+			n.score = score
+			z.insert(n)
+		}
+		return false
+	}
+	z.seq++
+	n = &node[M, S]{member: member, score: score, seq: z.seq}
+	z.nodes[member] = n
+	z.insert(n)
+	return true
+}
+
+// Incr adds delta to the score of member, adding member to the set
+// with a score of delta if it is not already in the set, and returns
+// the new score.
+func (z *ZSet[M, S]) Incr(member M, delta S) S {
+	score, _ := z.Score(member)
+	score += delta
+	z.Add(member, score)
+	return score
+}
+
+// Remove takes member out of the set. It returns false if
+// member was not in the set.
+func (z *ZSet[M, S]) Remove(member M) bool {
+	n, ok := z.nodes[member]
+	if !ok {
+		return false
+	}
+	z.unlink(n)
+	delete(z.nodes, member)
+	return true
+}
+
+func randomLevel() int {
+	// Each level is a quarter as likely as the one below it.
+	level := 1
+	for level < maxLevel && rand.IntN(4) == 0 {
+		level++
+	}
+	return level
+}
+
+// insert links n, which must not already be linked, into the skip list.
+func (z *ZSet[M, S]) insert(n *node[M, S]) {
+	var update [maxLevel]*node[M, S]
+	// rank[i] is the number of nodes before update[i].
+	var rank [maxLevel]int
+	x := z.head
+	for i := z.level - 1; i >= 0; i-- {
+		if i < z.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].forward != nil && x.levels[i].forward.less(n.score, n.seq) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+	level := randomLevel()
+	if level > z.level {
+		for i := z.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = z.head
+			// n is already in nodes, but not in the list.
+			update[i].levels[i].span = len(z.nodes) - 1
+		}
+		z.level = level
+	}
+	n.levels = make([]link[M, S], level)
+	for i := 0; i < level; i++ {
+		n.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = n
+		// The new node splits the span of the link it was put into.
+		n.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	// Links above the new node's levels now skip one more node.
+	for i := level; i < z.level; i++ {
+		update[i].levels[i].span++
+	}
+	if update[0] != z.head {
+		n.backward = update[0]
+	} else {
+		n.backward = nil
+	}
+	if n.levels[0].forward != nil {
+		n.levels[0].forward.backward = n
+	} else {
+		z.tail = n
+	}
+}
+
+// unlink takes n out of the skip list.
+func (z *ZSet[M, S]) unlink(n *node[M, S]) {
+	var update [maxLevel]*node[M, S]
+	x := z.head
+	for i := z.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && x.levels[i].forward.less(n.score, n.seq) {
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+	for i := 0; i < z.level; i++ {
+		if update[i].levels[i].forward == n {
+			update[i].levels[i].span += n.levels[i].span - 1
+			update[i].levels[i].forward = n.levels[i].forward
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	if n.levels[0].forward != nil {
+		n.levels[0].forward.backward = n.backward
+	} else {
+		z.tail = n.backward
+	}
+	for z.level > 1 && z.head.levels[z.level-1].forward == nil {
+		z.level--
+	}
+}
+
+// Rank returns the rank of member, which is the number of members
+// that come before it in ascending order of score, and whether it
+// is in the set.
+func (z *ZSet[M, S]) Rank(member M) (int, bool) {
+	n, ok := z.nodes[member]
+	if !ok {
+		return 0, false
+	}
+	rank := 0
+	x := z.head
+	for i := z.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && x.levels[i].forward.less(n.score, n.seq) {
+			rank += x.levels[i].span
+			x = x.levels[i].forward
+		}
+	}
+	return rank, true
+}
+
+// RevRank returns the rank of member in descending order of score,
+// and whether it is in the set.
+func (z *ZSet[M, S]) RevRank(member M) (int, bool) {
+	rank, ok := z.Rank(member)
+	if !ok {
+		return 0, false
+	}
+	return z.Len() - 1 - rank, true
+}
+
+// byRank returns the node with the given rank, which must be in range.
+func (z *ZSet[M, S]) byRank(rank int) *node[M, S] {
+	// Count the head as rank -1, so that a link's span takes
+	// us to the rank of the node it points to.
+	traversed := -1
+	x := z.head
+	for i := z.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && traversed+x.levels[i].span <= rank {
+			traversed += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		if traversed == rank {
+			return x
+		}
+	}
+	return nil
+}
+
+// RangeByRank returns an iterator over the members with ranks in
+// the closed range [start, stop] and their scores, in ascending
+// order of score. As in ZRANGE, a negative rank counts back from
+// the end, so that -1 is the last member and RangeByRank(0, -1)
+// covers every member, and the range is then clamped to the
+// members there are.
+func (z *ZSet[M, S]) RangeByRank(start int, stop int) iter.Seq2[M, S] {
+	return func(yield func(M, S) bool) {
+		n := z.Len()
+		start, stop := start, stop
+		if start < 0 {
+			start += n
+		}
+		if stop < 0 {
+			stop += n
+		}
+		start = max(start, 0)
+		stop = min(stop, n-1)
+		if start > stop {
+			return
+		}
+		x := z.byRank(start)
+		for i := start; i <= stop && x != nil; i++ {
+			next := x.levels[0].forward
+			if !yield(x.member, x.score) {
+				return
+			}
+			x = next
+		}
+	}
+}
+
+// RangeByScore returns an iterator over the members with scores in
+// the closed range [min, max] and their scores, in ascending order
+// of score.
+func (z *ZSet[M, S]) RangeByScore(min S, max S) iter.Seq2[M, S] {
+	return func(yield func(M, S) bool) {
+		x := z.head
+		for i := z.level - 1; i >= 0; i-- {
+			for x.levels[i].forward != nil && x.levels[i].forward.score < min {
+				x = x.levels[i].forward
+			}
+		}
+		for n := x.levels[0].forward; n != nil && n.score <= max; {
+			next := n.levels[0].forward
+			if !yield(n.member, n.score) {
+				return
+			}
+			n = next
+		}
+	}
+}
+
+// PopMin takes the member with the lowest score out of the set, and
+// returns it and its score. It returns false if the set is empty.
+func (z *ZSet[M, S]) PopMin() (M, S, bool) {
+	return z.pop(z.head.levels[0].forward)
+}
+
+// PopMax takes the member with the highest score out of the set, and
+// returns it and its score. It returns false if the set is empty.
+func (z *ZSet[M, S]) PopMax() (M, S, bool) {
+	return z.pop(z.tail)
+}
+
+func (z *ZSet[M, S]) pop(n *node[M, S]) (M, S, bool) {
+	if n == nil {
+		var member M
+		var score S
+		return member, score, false
+	}
+	z.Remove(n.member)
+	return n.member, n.score, true
+}
+
+// Iter iterates through every member of the set in ascending order
+// of score and calls function f using the member and its score
+// as arguments.
+func (z *ZSet[M, S]) Iter(f func(member M, score S)) {
+	for member, score := range z.All() {
+		f(member, score)
+	}
+}
+
+// All returns an iterator over every member of the set and its
+// score, in ascending order of score.
+func (z *ZSet[M, S]) All() iter.Seq2[M, S] {
+	return func(yield func(M, S) bool) {
+		for n := z.head.levels[0].forward; n != nil; {
+			next := n.levels[0].forward
+			if !yield(n.member, n.score) {
+				return
+			}
+			n = next
+		}
+	}
+}
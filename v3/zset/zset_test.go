@@ -0,0 +1,229 @@
+package zset
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type pair struct {
+	member string
+	score  int
+}
+
+func collect(seq func(yield func(string, int) bool)) []pair {
+	var got []pair
+	seq(func(member string, score int) bool {
+		got = append(got, pair{member, score})
+		return true
+	})
+	return got
+}
+
+func TestZSet(t *testing.T) {
+	z := New[string, int]()
+	if !z.Add("alice", 30) || !z.Add("bob", 10) || !z.Add("carol", 20) || !z.Add("dave", 20) {
+		t.Error("Expected new members to be added")
+	}
+	if z.Add("bob", 40) {
+		t.Error("Did not expect bob to be added twice")
+	}
+	if got := z.Incr("carol", 5); got != 25 {
+		t.Errorf("Expected carol to have 25, got %v", got)
+	}
+	if got := z.Incr("erin", 5); got != 5 {
+		t.Errorf("Expected erin to have 5, got %v", got)
+	}
+	want := []pair{{"erin", 5}, {"dave", 20}, {"carol", 25}, {"alice", 30}, {"bob", 40}}
+	if got := collect(z.All()); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if rank, ok := z.Rank("carol"); !ok || rank != 2 {
+		t.Errorf("Expected carol to have rank 2, got %v", rank)
+	}
+	if rank, ok := z.RevRank("alice"); !ok || rank != 1 {
+		t.Errorf("Expected alice to have reverse rank 1, got %v", rank)
+	}
+	if _, ok := z.Rank("zed"); ok {
+		t.Error("Did not expect zed to have a rank")
+	}
+	if score, ok := z.Score("dave"); !ok || score != 20 {
+		t.Errorf("Expected dave to have 20, got %v", score)
+	}
+	if got := collect(z.RangeByScore(20, 30)); !slices.Equal(want[1:4], got) {
+		t.Errorf("Expected %v, got %v", want[1:4], got)
+	}
+	if got := collect(z.RangeByRank(3, 10)); !slices.Equal(want[3:], got) {
+		t.Errorf("Expected %v, got %v", want[3:], got)
+	}
+	if member, score, ok := z.PopMin(); !ok || member != "erin" || score != 5 {
+		t.Errorf("Expected to pop erin 5, got %v %v", member, score)
+	}
+	if member, score, ok := z.PopMax(); !ok || member != "bob" || score != 40 {
+		t.Errorf("Expected to pop bob 40, got %v %v", member, score)
+	}
+	if !z.Remove("dave") || z.Remove("dave") {
+		t.Error("Expected to remove dave once")
+	}
+	if z.Len() != 2 {
+		t.Errorf("Expected 2 members, got %v", z.Len())
+	}
+}
+
+func TestTies(t *testing.T) {
+	z := New[string, int]()
+	for _, m := range []string{"c", "a", "b"} {
+		z.Add(m, 1)
+	}
+	// Changing a score keeps the member's place among ties.
+	z.Add("c", 2)
+	z.Add("c", 1)
+	want := []pair{{"c", 1}, {"a", 1}, {"b", 1}}
+	if got := collect(z.All()); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	// Ties are not ordered by member, as they would be in Redis.
+	z.Add("d", 0)
+	z.Add("e", 2)
+	if rank, _ := z.Rank("a"); rank != 2 {
+		t.Errorf("Expected a to have rank 2, got %v", rank)
+	}
+	want = []pair{{"c", 1}, {"a", 1}, {"b", 1}}
+	if got := collect(z.RangeByRank(1, 3)); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := collect(z.RangeByScore(1, 1)); !slices.Equal(want, got) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if member, _, _ := z.PopMin(); member != "d" {
+		t.Errorf("Expected to pop d, got %v", member)
+	}
+	if member, _, _ := z.PopMin(); member != "c" {
+		t.Errorf("Expected to pop c, got %v", member)
+	}
+}
+
+func TestRangeByRank(t *testing.T) {
+	z := New[string, int]()
+	for i, m := range []string{"a", "b", "c", "d", "e"} {
+		z.Add(m, i)
+	}
+	var tests = []struct {
+		start int
+		stop  int
+		want  string
+	}{
+		{0, -1, "abcde"},
+		{0, 4, "abcde"},
+		{0, 0, "a"},
+		{1, 3, "bcd"},
+		{-2, -1, "de"},
+		{-1, -1, "e"},
+		{-3, 3, "cd"},
+		{-100, 1, "ab"},
+		{3, 100, "de"},
+		{2, 1, ""},
+		{-1, -2, ""},
+		{5, 10, ""},
+		{0, -6, ""},
+	}
+	for _, test := range tests {
+		got := ""
+		for m := range z.RangeByRank(test.start, test.stop) {
+			got += m
+		}
+		if got != test.want {
+			t.Errorf("Expected ranks [%v, %v] to be %q, got %q", test.start, test.stop, test.want, got)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	z := New[int, float64]()
+	if _, _, ok := z.PopMin(); ok {
+		t.Error("Did not expect to pop from an empty set")
+	}
+	if _, _, ok := z.PopMax(); ok {
+		t.Error("Did not expect to pop from an empty set")
+	}
+	for range z.RangeByRank(0, 10) {
+		t.Error("Did not expect any members")
+	}
+}
+
+// TestRandom checks ranks and ranges against a sorted slice.
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	z := New[int, int]()
+	scores := make(map[int]int)
+	seqs := make(map[int]int)
+	for i := 0; i < 3000; i++ {
+		m := r.Intn(300)
+		switch r.Intn(4) {
+		case 0:
+			z.Remove(m)
+			delete(scores, m)
+			delete(seqs, m)
+		case 1:
+			if m, _, ok := z.PopMin(); ok {
+				if want := sorted(scores, seqs); m != want[0] {
+					t.Fatalf("Expected to pop %v, got %v", want[0], m)
+				}
+				delete(scores, m)
+				delete(seqs, m)
+			}
+		default:
+			s := r.Intn(50)
+			z.Add(m, s)
+			if _, ok := scores[m]; !ok {
+				seqs[m] = i
+			}
+			scores[m] = s
+		}
+		want := sorted(scores, seqs)
+		if z.Len() != len(want) {
+			t.Fatalf("Expected %v members, got %v", len(want), z.Len())
+		}
+		if len(want) == 0 {
+			continue
+		}
+		k := r.Intn(len(want))
+		if rank, _ := z.Rank(want[k]); rank != k {
+			t.Fatalf("Expected %v to have rank %v, got %v", want[k], k, rank)
+		}
+		lo := r.Intn(len(want))
+		hi := lo + 1 + r.Intn(len(want)-lo)
+		// Ask for ranks [lo, hi) as an inclusive range, counting
+		// from the end half of the time. Empty ranges cannot be
+		// asked for this way, since a stop of -1 means the last
+		// member; TestRangeByRank covers them.
+		start, stop := lo, hi-1
+		if r.Intn(2) == 0 {
+			start, stop = lo-len(want), hi-1-len(want)
+		}
+		var got []int
+		for m := range z.RangeByRank(start, stop) {
+			got = append(got, m)
+		}
+		if !slices.Equal(want[lo:hi], got) {
+			t.Fatalf("Expected ranks [%v, %v] to be %v, got %v", start, stop, want[lo:hi], got)
+		}
+	}
+}
+
+// sorted returns the members in order of score, then of when
+// they were first added.
+func sorted(scores map[int]int, seqs map[int]int) []int {
+	var members []int
+	for m := range scores {
+		members = append(members, m)
+	}
+	slices.SortFunc(members, func(a, b int) int {
+		if c := cmp.Compare(scores[a], scores[b]); c != 0 {
+			return c
+		}
+		return cmp.Compare(seqs[a], seqs[b])
+	})
+	return members
+}